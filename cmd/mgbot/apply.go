@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+func runApply(ctx context.Context, a *app, args []string) error {
+	var dryRun bool
+
+	fs := a.newFlagSet("apply", "MANIFEST")
+	fs.BoolVar(&dryRun, "dry-run", false, "only print the plan")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	manifest, err := v1.LoadManifest(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	plan, err := a.client.PlanManifest(ctx, manifest)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(a.stdout, plan)
+	if dryRun || plan.Empty() {
+		return nil
+	}
+
+	if err := a.client.ApplyPlan(ctx, plan); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.stdout, "Applied.")
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+	"github.com/retailcrm/mg-bot-api-client-go/v1/mgtest"
+)
+
+func TestRun_Apply(t *testing.T) {
+	srv := mgtest.NewServer()
+	defer srv.Close()
+
+	srv.AddCommand(v1.CommandsResponseItem{Name: "stale", Description: "Stale"})
+
+	dir, err := ioutil.TempDir("", "mgbot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "bot.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(`
+name: Support bot
+commands:
+  - name: start
+    description: Start conversation
+`), 0600))
+
+	out, err := runCLI(t, srv, "apply", "-dry-run", path)
+	require.NoError(t, err)
+	assert.Contains(t, out, `+ /start: "Start conversation"`)
+	assert.Contains(t, out, "- /stale")
+	assert.Len(t, srv.Commands(), 1)
+
+	out, err = runCLI(t, srv, "apply", path)
+	require.NoError(t, err)
+	assert.Contains(t, out, "Applied.")
+	assert.Equal(t, "Support bot", srv.Bot().Name)
+	require.Len(t, srv.Commands(), 1)
+	assert.Equal(t, "start", srv.Commands()[0].Name)
+
+	out, err = runCLI(t, srv, "apply", path)
+	require.NoError(t, err)
+	assert.Equal(t, "No changes.\n", out)
+}
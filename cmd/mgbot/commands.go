@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+var botCommands = map[string]command{
+	"list":   {"list bot commands", runCommandsList},
+	"edit":   {"create or update the bot command", runCommandEdit},
+	"delete": {"delete the bot command", runCommandDelete},
+}
+
+func runCommandsList(ctx context.Context, a *app, args []string) error {
+	var (
+		list listFlags
+		name string
+	)
+
+	fs := a.newFlagSet("commands list", "")
+	list.register(fs)
+	fs.StringVar(&name, "name", "", "filter by command name")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	since, until, err := list.period()
+	if err != nil {
+		return err
+	}
+
+	cmds, _, err := a.client.CommandsContext(ctx, v1.CommandsRequest{
+		ID:    list.id,
+		Name:  name,
+		Since: since,
+		Until: until,
+		Limit: list.limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(cmds)
+}
+
+func runCommandEdit(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("commands edit", "NAME DESCRIPTION")
+	if err := parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+
+	resp, _, err := a.client.CommandEditContext(ctx, v1.CommandEditRequest{
+		Name:        fs.Arg(0),
+		Description: fs.Arg(1),
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp)
+}
+
+func runCommandDelete(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("commands delete", "NAME")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	resp, _, err := a.client.CommandDeleteContext(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp)
+}
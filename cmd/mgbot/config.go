@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+const (
+	envURL     = "MG_BOT_URL"
+	envToken   = "MG_BOT_TOKEN"
+	defaultEnv = ".env"
+)
+
+// config holds the connection settings.
+type config struct {
+	url     string
+	token   string
+	envFile string
+	debug   bool
+}
+
+func (c *config) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.url, "url", "", "MG Bot API URL (default $"+envURL+")")
+	fs.StringVar(&c.token, "token", "", "bot token (default $"+envToken+")")
+	fs.StringVar(&c.envFile, "env", defaultEnv, "file with "+envURL+" and "+envToken+" variables")
+	fs.BoolVar(&c.debug, "debug", false, "log API requests and responses")
+}
+
+// resolve fills empty settings from the environment and the .env file. The default .env file is optional.
+func (c *config) resolve(getenv func(string) string) error {
+	fileEnv := map[string]string{}
+	if c.envFile != "" {
+		values, err := godotenv.Read(c.envFile)
+		switch {
+		case err == nil:
+			fileEnv = values
+		case c.envFile != defaultEnv || !errors.Is(err, os.ErrNotExist):
+			return fmt.Errorf("read %s: %w", c.envFile, err)
+		}
+	}
+
+	lookup := func(name string) string {
+		if value := getenv(name); value != "" {
+			return value
+		}
+
+		return fileEnv[name]
+	}
+
+	if c.url == "" {
+		c.url = lookup(envURL)
+	}
+
+	if c.token == "" {
+		c.token = lookup(envToken)
+	}
+
+	if c.url == "" || c.token == "" {
+		return usageError("API URL and token are required, use -url and -token flags, " +
+			envURL + " and " + envToken + " variables or .env file")
+	}
+
+	return nil
+}
+
+func (c *config) client(stderr io.Writer) *v1.MgClient {
+	var opts []v1.Option
+	if c.debug {
+		opts = append(opts, v1.OptionDebug(), v1.OptionLogger(log.New(stderr, "", log.LstdFlags)))
+	}
+
+	return v1.New(c.url, c.token, opts...)
+}
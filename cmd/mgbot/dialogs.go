@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+var dialogCommands = map[string]command{
+	"list":     {"list dialogs", runDialogsList},
+	"assign":   {"assign the dialog to a user or a bot", runDialogAssign},
+	"unassign": {"unassign the dialog", runDialogUnassign},
+	"close":    {"close the dialog", runDialogClose},
+	"tag":      {"add or delete dialog tags", runDialogTag},
+}
+
+func runDialogsList(ctx context.Context, a *app, args []string) error {
+	var (
+		list   listFlags
+		chatID uint64
+		userID uint64
+		active bool
+	)
+
+	fs := a.newFlagSet("dialogs list", "")
+	list.register(fs)
+	fs.Uint64Var(&chatID, "chat-id", 0, "filter by chat id")
+	fs.Uint64Var(&userID, "user-id", 0, "filter by responsible user id")
+	fs.BoolVar(&active, "active", false, "only active dialogs")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	since, until, err := list.period()
+	if err != nil {
+		return err
+	}
+
+	request := v1.DialogsRequest{
+		ID:     list.id,
+		Active: flagUint8(active),
+		Since:  since,
+		Until:  until,
+		Limit:  list.limit,
+	}
+
+	if chatID != 0 {
+		request.ChatID = strconv.FormatUint(chatID, 10)
+	}
+
+	if userID != 0 {
+		request.UserID = strconv.FormatUint(userID, 10)
+	}
+
+	dialogs, _, err := a.client.DialogsContext(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return a.print(dialogs)
+}
+
+func runDialogAssign(ctx context.Context, a *app, args []string) error {
+	var userID, botID uint64
+
+	fs := a.newFlagSet("dialogs assign", "DIALOG_ID")
+	fs.Uint64Var(&userID, "user", 0, "responsible user id")
+	fs.Uint64Var(&botID, "bot", 0, "responsible bot id")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	if (userID == 0) == (botID == 0) {
+		return usageError("dialogs assign: exactly one of -user and -bot is required")
+	}
+
+	dialogID, err := parseID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	resp, _, err := a.client.DialogAssignContext(ctx, v1.DialogAssignRequest{
+		DialogID: dialogID,
+		UserID:   userID,
+		BotID:    botID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp)
+}
+
+func runDialogUnassign(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("dialogs unassign", "DIALOG_ID")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	dialogID, err := parseID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	resp, _, err := a.client.DialogUnassignContext(ctx, dialogID)
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp)
+}
+
+func runDialogClose(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("dialogs close", "DIALOG_ID")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	dialogID, err := parseID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	resp, _, err := a.client.DialogCloseContext(ctx, dialogID)
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp)
+}
+
+func runDialogTag(ctx context.Context, a *app, args []string) error {
+	var (
+		remove bool
+		color  string
+	)
+
+	fs := a.newFlagSet("dialogs tag", "DIALOG_ID TAG...")
+	fs.BoolVar(&remove, "delete", false, "delete the tags instead of adding")
+	fs.StringVar(&color, "color", "", "color code of added tags, e.g. light-red")
+	if err := parse(fs, args, 2, -1); err != nil {
+		return err
+	}
+
+	dialogID, err := parseID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	names := fs.Args()[1:]
+	if remove {
+		tags := make([]v1.TagsDelete, 0, len(names))
+		for _, name := range names {
+			tags = append(tags, v1.TagsDelete{Name: name})
+		}
+
+		_, err = a.client.DialogTagsDeleteContext(ctx, v1.DialogTagsDeleteRequest{DialogID: dialogID, Tags: tags})
+	} else {
+		tags := make([]v1.TagsAdd, 0, len(names))
+		for _, name := range names {
+			tag := v1.TagsAdd{Name: name}
+			if color != "" {
+				tag.ColorCode = &color
+			}
+
+			tags = append(tags, tag)
+		}
+
+		_, err = a.client.DialogsTagsAddContext(ctx, v1.DialogTagsAddRequest{DialogID: dialogID, Tags: tags})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return a.print(map[string]interface{}{"dialog_id": dialogID, "tags": names, "deleted": remove})
+}
+
+// parseID parses the positional id argument.
+func parseID(value string) (uint64, error) {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, usageError("invalid id %q", value)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+var fileCommands = map[string]command{
+	"upload": {"upload a local file or a file by URL", runFileUpload},
+	"get":    {"show file info and optionally download the file", runFileGet},
+}
+
+func runFileUpload(ctx context.Context, a *app, args []string) error {
+	var url string
+
+	fs := a.newFlagSet("files upload", "[PATH]")
+	fs.StringVar(&url, "url", "", "upload the file by URL instead of a local path")
+	if err := parse(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	if (url == "") == (fs.NArg() == 0) {
+		return usageError("files upload: either PATH or -url is required")
+	}
+
+	var (
+		resp v1.UploadFileResponse
+		err  error
+	)
+
+	if url != "" {
+		resp, _, err = a.client.UploadFileByURLContext(ctx, v1.UploadFileByUrlRequest{Url: url})
+	} else {
+		resp, _, err = a.client.UploadFilePath(ctx, fs.Arg(0))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp)
+}
+
+func runFileGet(ctx context.Context, a *app, args []string) error {
+	var out string
+
+	fs := a.newFlagSet("files get", "FILE_ID")
+	fs.StringVar(&out, "o", "", "download the file contents to the path")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	if out == "" {
+		resp, _, err := a.client.GetFileContext(ctx, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+
+		return a.print(resp)
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+
+	info, err := a.client.DownloadFile(ctx, fs.Arg(0), f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(out)
+		return err
+	}
+
+	return a.print(info)
+}
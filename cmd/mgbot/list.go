@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+// listFlags are the filters common to all list commands.
+type listFlags struct {
+	id    uint64
+	limit int
+	since string
+	until string
+}
+
+func (f *listFlags) register(fs *flag.FlagSet) {
+	fs.Uint64Var(&f.id, "id", 0, "filter by id")
+	fs.IntVar(&f.limit, "limit", 0, "maximum number of items (default is set by the API)")
+	fs.StringVar(&f.since, "since", "", "created since the time, e.g. 2024-01-31T10:00:00Z")
+	fs.StringVar(&f.until, "until", "", "created until the time")
+}
+
+// period parses -since and -until flags.
+func (f *listFlags) period() (since, until time.Time, err error) {
+	sinceTS, err := v1.ParseTimestamp(f.since)
+	if err != nil {
+		return since, until, fmt.Errorf("%w: -since: %v", errUsage, err)
+	}
+
+	untilTS, err := v1.ParseTimestamp(f.until)
+	if err != nil {
+		return since, until, fmt.Errorf("%w: -until: %v", errUsage, err)
+	}
+
+	return sinceTS.Time, untilTS.Time, nil
+}
+
+// print writes the value to stdout as indented JSON.
+func (a *app) print(value interface{}) error {
+	encoder := json.NewEncoder(a.stdout)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
+func flagUint8(value bool) uint8 {
+	if value {
+		return 1
+	}
+
+	return 0
+}
+
+func runBots(ctx context.Context, a *app, args []string) error {
+	var (
+		list   listFlags
+		active bool
+		role   string
+	)
+
+	fs := a.newFlagSet("bots", "")
+	list.register(fs)
+	fs.BoolVar(&active, "active", false, "only active bots")
+	fs.StringVar(&role, "role", "", "filter by role")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	since, until, err := list.period()
+	if err != nil {
+		return err
+	}
+
+	bots, _, err := a.client.BotsContext(ctx, v1.BotsRequest{
+		ID:     list.id,
+		Active: flagUint8(active),
+		Role:   role,
+		Since:  since,
+		Until:  until,
+		Limit:  list.limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(bots)
+}
+
+func runChannels(ctx context.Context, a *app, args []string) error {
+	var (
+		list   listFlags
+		active bool
+		types  string
+	)
+
+	fs := a.newFlagSet("channels", "")
+	list.register(fs)
+	fs.BoolVar(&active, "active", false, "only active channels")
+	fs.StringVar(&types, "types", "", "comma separated channel types, e.g. telegram,whatsapp")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	since, until, err := list.period()
+	if err != nil {
+		return err
+	}
+
+	channels, _, err := a.client.ChannelsContext(ctx, v1.ChannelsRequest{
+		ID:     list.id,
+		Types:  splitList(types),
+		Active: flagUint8(active),
+		Since:  since,
+		Until:  until,
+		Limit:  list.limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(channels)
+}
+
+func runUsers(ctx context.Context, a *app, args []string) error {
+	var (
+		list       listFlags
+		externalID string
+		online     bool
+		active     bool
+	)
+
+	fs := a.newFlagSet("users", "")
+	list.register(fs)
+	fs.StringVar(&externalID, "external-id", "", "filter by external id")
+	fs.BoolVar(&online, "online", false, "only online users")
+	fs.BoolVar(&active, "active", false, "only active users")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	since, until, err := list.period()
+	if err != nil {
+		return err
+	}
+
+	users, _, err := a.client.UsersContext(ctx, v1.UsersRequest{
+		ID:         list.id,
+		ExternalID: externalID,
+		Online:     flagUint8(online),
+		Active:     flagUint8(active),
+		Since:      since,
+		Until:      until,
+		Limit:      list.limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(users)
+}
+
+func runCustomers(ctx context.Context, a *app, args []string) error {
+	var (
+		list       listFlags
+		channelID  uint64
+		externalID string
+	)
+
+	fs := a.newFlagSet("customers", "")
+	list.register(fs)
+	fs.Uint64Var(&channelID, "channel-id", 0, "filter by channel id")
+	fs.StringVar(&externalID, "external-id", "", "filter by external id")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	since, until, err := list.period()
+	if err != nil {
+		return err
+	}
+
+	customers, _, err := a.client.CustomersContext(ctx, v1.CustomersRequest{
+		ID:         list.id,
+		ChannelID:  channelID,
+		ExternalID: externalID,
+		Since:      since,
+		Until:      until,
+		Limit:      list.limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(customers)
+}
+
+func runChats(ctx context.Context, a *app, args []string) error {
+	var (
+		list       listFlags
+		channelID  uint64
+		customerID uint64
+	)
+
+	fs := a.newFlagSet("chats", "")
+	list.register(fs)
+	fs.Uint64Var(&channelID, "channel-id", 0, "filter by channel id")
+	fs.Uint64Var(&customerID, "customer-id", 0, "filter by customer id")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	since, until, err := list.period()
+	if err != nil {
+		return err
+	}
+
+	chats, _, err := a.client.ChatsContext(ctx, v1.ChatsRequest{
+		ID:         list.id,
+		ChannelID:  channelID,
+		CustomerID: customerID,
+		Since:      since,
+		Until:      until,
+		Limit:      list.limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(chats)
+}
+
+func runInfo(ctx context.Context, a *app, args []string) error {
+	var name, avatar, roles string
+
+	fs := a.newFlagSet("info", "")
+	fs.StringVar(&name, "name", "", "set the bot name")
+	fs.StringVar(&avatar, "avatar", "", "set the bot avatar URL")
+	fs.StringVar(&roles, "roles", "", "set comma separated bot roles, e.g. distributor,responsible")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	bots, _, err := a.client.BotsContext(ctx, v1.BotsRequest{Self: 1})
+	if err != nil {
+		return err
+	}
+
+	if len(bots) == 0 {
+		return errors.New("bot not found")
+	}
+
+	bot := bots[0]
+	if name == "" && avatar == "" && roles == "" {
+		return a.print(bot)
+	}
+
+	// InfoRequest sends all fields, so the fields which are not set keep current values.
+	request := v1.InfoRequest{Name: bot.Name, Avatar: bot.AvatarUrl, Roles: bot.Roles}
+	if name != "" {
+		request.Name = name
+	}
+
+	if avatar != "" {
+		request.Avatar = avatar
+	}
+
+	if roles != "" {
+		request.Roles = splitList(roles)
+	}
+
+	resp, _, err := a.client.InfoContext(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp)
+}
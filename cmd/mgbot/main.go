@@ -0,0 +1,223 @@
+// Command mgbot operates an MG bot from the command line: lists bots, channels, chats and other entities,
+// sends messages, manages dialogs, bot commands and files, applies bot manifests.
+//
+// Usage:
+//
+//	mgbot [-url URL] [-token TOKEN] [-env FILE] [-debug] COMMAND [ARGS]
+//
+// The API URL and the bot token are taken from the flags, MG_BOT_URL and MG_BOT_TOKEN environment variables
+// or the .env file, in that order. Results are printed as JSON.
+//
+// Examples:
+//
+//	mgbot chats -limit 10
+//	mgbot messages send -scope private 12 "Hello!"
+//	mgbot dialogs assign -user 6 34
+//	mgbot commands edit start "Start conversation"
+//	mgbot files upload ./invoice.pdf
+//	mgbot apply -dry-run bot.yaml
+//	mgbot tail -events message_new,dialog_assign -chat-id 12 -out events.jsonl
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+const (
+	exitError = 1
+	exitUsage = 2
+)
+
+// errUsage is returned for invalid command line arguments.
+var errUsage = errors.New("invalid usage")
+
+// app holds the state shared by all commands.
+type app struct {
+	client *v1.MgClient
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// command is a CLI subcommand.
+type command struct {
+	usage string
+	run   func(ctx context.Context, a *app, args []string) error
+}
+
+// commands lists top level subcommands.
+var commands = map[string]command{
+	"bots":      {"list bots", runBots},
+	"channels":  {"list channels", runChannels},
+	"users":     {"list users", runUsers},
+	"customers": {"list customers", runCustomers},
+	"chats":     {"list chats", runChats},
+	"dialogs":   {"list, assign, unassign, close and tag dialogs", group(dialogCommands)},
+	"messages":  {"list, send, edit and delete messages", group(messageCommands)},
+	"commands":  {"list, edit and delete bot commands", group(botCommands)},
+	"files":     {"upload and get files", group(fileCommands)},
+	"info":      {"show or update the bot name, avatar and roles", runInfo},
+	"tail":      {"print websocket events as they arrive", runTail},
+	"apply":     {"bring the bot profile and commands in line with the manifest", runApply},
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		<-signals
+		cancel()
+	}()
+
+	err := run(ctx, os.Args[1:], os.Getenv, os.Stdout, os.Stderr)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(os.Stderr, "mgbot: %v\n", err)
+		os.Exit(exitUsage)
+	default:
+		fmt.Fprintf(os.Stderr, "mgbot: %v\n", err)
+		os.Exit(exitError)
+	}
+}
+
+// run parses global flags, creates the client and runs the subcommand.
+func run(ctx context.Context, args []string, getenv func(string) string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("mgbot", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: mgbot [flags] COMMAND [ARGS]\n\nCommands:")
+		for _, name := range commandNames(commands) {
+			fmt.Fprintf(stderr, "  %-10s %s\n", name, commands[name].usage)
+		}
+
+		fmt.Fprintln(stderr, "\nFlags:")
+		fs.PrintDefaults()
+	}
+
+	var cfg config
+	cfg.register(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return usageError("command is required")
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		return usageError("unknown command %q", fs.Arg(0))
+	}
+
+	if err := cfg.resolve(getenv); err != nil {
+		return err
+	}
+
+	a := &app{client: cfg.client(stderr), stdout: stdout, stderr: stderr}
+
+	return cmd.run(ctx, a, fs.Args()[1:])
+}
+
+// group returns the command which dispatches to one of the nested commands.
+func group(nested map[string]command) func(ctx context.Context, a *app, args []string) error {
+	return func(ctx context.Context, a *app, args []string) error {
+		if len(args) == 0 || args[0] == "-h" || args[0] == "-help" {
+			fmt.Fprintln(a.stderr, "Commands:")
+			for _, name := range commandNames(nested) {
+				fmt.Fprintf(a.stderr, "  %-10s %s\n", name, nested[name].usage)
+			}
+
+			if len(args) == 0 {
+				return usageError("command is required")
+			}
+
+			return flag.ErrHelp
+		}
+
+		cmd, ok := nested[args[0]]
+		if !ok {
+			return usageError("unknown command %q", args[0])
+		}
+
+		return cmd.run(ctx, a, args[1:])
+	}
+}
+
+func commandNames(cmds map[string]command) []string {
+	names := make([]string, 0, len(cmds))
+	for name := range cmds {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func usageError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", errUsage, fmt.Sprintf(format, args...))
+}
+
+// newFlagSet returns the flag set of the subcommand which prints errors and usage to stderr.
+func (a *app) newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: mgbot %s [flags] %s\n", name, args)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+// parse parses the subcommand flags and checks the number of positional arguments.
+// Negative maxArgs means any number of arguments.
+func parse(fs *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	if fs.NArg() < minArgs || (maxArgs >= 0 && fs.NArg() > maxArgs) {
+		fs.Usage()
+		return usageError("%s: wrong number of arguments", fs.Name())
+	}
+
+	return nil
+}
+
+// splitList splits a comma separated flag value.
+func splitList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	items := strings.Split(value, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+
+	return items
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+	"github.com/retailcrm/mg-bot-api-client-go/v1/mgtest"
+)
+
+func noEnv(string) string { return "" }
+
+// runCLI runs the command against the server and returns stdout.
+func runCLI(t *testing.T, srv *mgtest.Server, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"-env", "", "-url", srv.URL, "-token", mgtest.Token}, args...)
+	err := run(context.Background(), args, noEnv, &stdout, &stderr)
+
+	return stdout.String(), err
+}
+
+func TestRun_List(t *testing.T) {
+	srv := mgtest.NewServer()
+	defer srv.Close()
+
+	channel := srv.AddChannel(v1.ChannelResponseItem{Type: v1.ChannelTypeTelegram, Name: "Shop", IsActive: true})
+	chat := srv.AddChat(v1.ChatResponseItem{Name: "John", Channel: v1.Channel{ID: channel.ID, Type: channel.Type}})
+	srv.AddChat(v1.ChatResponseItem{Name: "Jane"})
+
+	out, err := runCLI(t, srv, "chats", "-channel-id", fmt.Sprint(channel.ID), "-limit", "10")
+	require.NoError(t, err)
+
+	var chats []v1.ChatResponseItem
+	require.NoError(t, json.Unmarshal([]byte(out), &chats))
+	require.Len(t, chats, 1)
+	assert.Equal(t, chat.ID, chats[0].ID)
+
+	out, err = runCLI(t, srv, "info")
+	require.NoError(t, err)
+	assert.Contains(t, out, `"id": `)
+
+	_, err = runCLI(t, srv, "chats", "-since", "yesterday")
+	assert.True(t, errors.Is(err, errUsage))
+}
+
+func TestRun_Messages(t *testing.T) {
+	srv := mgtest.NewServer()
+	defer srv.Close()
+
+	chat := srv.AddChat(v1.ChatResponseItem{})
+
+	out, err := runCLI(t, srv, "messages", "send", "-scope", "private", fmt.Sprint(chat.ID), "Hello,", "world")
+	require.NoError(t, err)
+
+	var resp v1.MessageSendResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	require.Len(t, srv.Messages(), 1)
+	assert.Equal(t, resp.MessageID, srv.Messages()[0].ID)
+	assert.Equal(t, chat.ID, srv.Messages()[0].ChatID)
+	assert.Equal(t, "Hello, world", srv.Messages()[0].Content)
+	assert.Equal(t, v1.MessageScopePrivate, srv.Messages()[0].Scope)
+
+	_, err = runCLI(t, srv, "messages", "edit", fmt.Sprint(resp.MessageID), "Hi")
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", srv.Messages()[0].Content)
+
+	_, err = runCLI(t, srv, "messages", "delete", fmt.Sprint(resp.MessageID))
+	require.NoError(t, err)
+	assert.Empty(t, srv.Messages())
+
+	_, err = runCLI(t, srv, "messages", "send", fmt.Sprint(chat.ID))
+	assert.True(t, errors.Is(err, errUsage))
+
+	_, err = runCLI(t, srv, "messages", "send", "1000", "Hello")
+	assert.True(t, v1.IsNotFound(err))
+}
+
+func TestRun_Dialogs(t *testing.T) {
+	srv := mgtest.NewServer()
+	defer srv.Close()
+
+	chat := srv.AddChat(v1.ChatResponseItem{})
+	dialog := srv.AddDialog(v1.DialogResponseItem{ChatID: chat.ID, IsActive: true})
+	user := srv.AddUser(v1.UsersResponseItem{FirstName: "Jane", IsActive: true})
+
+	_, err := runCLI(t, srv, "dialogs", "assign", "-user", fmt.Sprint(user.ID), fmt.Sprint(dialog.ID))
+	require.NoError(t, err)
+	assert.EqualValues(t, user.ID, srv.Dialogs()[0].Responsible.ID)
+
+	_, err = runCLI(t, srv, "dialogs", "tag", "-color", "red", fmt.Sprint(dialog.ID), "vip", "urgent")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"vip", "urgent"}, srv.DialogTags(dialog.ID))
+
+	_, err = runCLI(t, srv, "dialogs", "tag", "-delete", fmt.Sprint(dialog.ID), "urgent")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vip"}, srv.DialogTags(dialog.ID))
+
+	_, err = runCLI(t, srv, "dialogs", "close", fmt.Sprint(dialog.ID))
+	require.NoError(t, err)
+	assert.False(t, srv.Dialogs()[0].IsActive)
+
+	_, err = runCLI(t, srv, "dialogs", "assign", "-user", "1", "-bot", "2", fmt.Sprint(dialog.ID))
+	assert.True(t, errors.Is(err, errUsage))
+}
+
+func TestRun_Commands(t *testing.T) {
+	srv := mgtest.NewServer()
+	defer srv.Close()
+
+	_, err := runCLI(t, srv, "commands", "edit", "start", "Start conversation")
+	require.NoError(t, err)
+
+	out, err := runCLI(t, srv, "commands", "list", "-name", "start")
+	require.NoError(t, err)
+
+	var cmds []v1.CommandsResponseItem
+	require.NoError(t, json.Unmarshal([]byte(out), &cmds))
+	require.Len(t, cmds, 1)
+	assert.Equal(t, "Start conversation", cmds[0].Description)
+
+	_, err = runCLI(t, srv, "commands", "delete", "start")
+	require.NoError(t, err)
+	assert.Empty(t, srv.Commands())
+}
+
+func TestRun_Files(t *testing.T) {
+	srv := mgtest.NewServer()
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "mgbot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "notes.txt")
+	require.NoError(t, ioutil.WriteFile(path, []byte("Hello, world"), 0600))
+
+	out, err := runCLI(t, srv, "files", "upload", path)
+	require.NoError(t, err)
+
+	var uploaded v1.UploadFileResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &uploaded))
+	assert.Equal(t, 12, uploaded.Size)
+
+	downloaded := filepath.Join(dir, "downloaded.txt")
+	_, err = runCLI(t, srv, "files", "get", "-o", downloaded, uploaded.ID)
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(downloaded)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, world", string(data))
+
+	_, err = runCLI(t, srv, "files", "upload")
+	assert.True(t, errors.Is(err, errUsage))
+}
+
+func TestRun_Config(t *testing.T) {
+	srv := mgtest.NewServer()
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "mgbot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	envFile := filepath.Join(dir, "bot.env")
+	require.NoError(t, ioutil.WriteFile(envFile, []byte(envURL+"="+srv.URL+"\n"+envToken+"=wrong\n"), 0600))
+
+	getenv := func(name string) string {
+		if name == envToken {
+			return mgtest.Token
+		}
+
+		return ""
+	}
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, run(context.Background(), []string{"-env", envFile, "bots"}, getenv, &stdout, &stderr))
+
+	err = run(context.Background(), []string{"-env", envFile, "-token", "wrong", "bots"}, getenv, &stdout, &stderr)
+	assert.True(t, v1.IsUnauthorized(err))
+
+	err = run(context.Background(), []string{"-env", filepath.Join(dir, "missing.env"), "bots"}, noEnv, &stdout, &stderr)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+
+	err = run(context.Background(), []string{"-env", "", "bots"}, noEnv, &stdout, &stderr)
+	assert.True(t, errors.Is(err, errUsage))
+}
+
+func TestRun_Usage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	err := run(context.Background(), nil, noEnv, &stdout, &stderr)
+	assert.True(t, errors.Is(err, errUsage))
+	assert.Contains(t, stderr.String(), "messages")
+
+	err = run(context.Background(), []string{"unknown"}, noEnv, &stdout, &stderr)
+	assert.True(t, errors.Is(err, errUsage))
+
+	err = run(context.Background(), []string{"-h"}, noEnv, &stdout, &stderr)
+	assert.True(t, errors.Is(err, flag.ErrHelp))
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+var messageCommands = map[string]command{
+	"list":   {"list messages", runMessagesList},
+	"send":   {"send a text message to the chat", runMessageSend},
+	"edit":   {"edit the message text", runMessageEdit},
+	"delete": {"delete the message", runMessageDelete},
+}
+
+func runMessagesList(ctx context.Context, a *app, args []string) error {
+	var (
+		list             listFlags
+		chatID, dialogID uint64
+		msgType, scope   string
+	)
+
+	fs := a.newFlagSet("messages list", "")
+	list.register(fs)
+	fs.Uint64Var(&chatID, "chat-id", 0, "filter by chat id")
+	fs.Uint64Var(&dialogID, "dialog-id", 0, "filter by dialog id")
+	fs.StringVar(&msgType, "type", "", "filter by message type, e.g. text")
+	fs.StringVar(&scope, "scope", "", "filter by scope: public or private")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	since, until, err := list.period()
+	if err != nil {
+		return err
+	}
+
+	request := v1.MessagesRequest{
+		ChatID:   chatID,
+		DialogID: dialogID,
+		Type:     msgType,
+		Scope:    scope,
+		Since:    since,
+		Until:    until,
+		Limit:    list.limit,
+	}
+
+	if list.id != 0 {
+		request.ID = []int{int(list.id)}
+	}
+
+	messages, _, err := a.client.MessagesContext(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return a.print(messages)
+}
+
+func runMessageSend(ctx context.Context, a *app, args []string) error {
+	var (
+		scope   string
+		quoteID uint64
+	)
+
+	fs := a.newFlagSet("messages send", "CHAT_ID TEXT...")
+	fs.StringVar(&scope, "scope", v1.MessageScopePublic, "message scope: public or private")
+	fs.Uint64Var(&quoteID, "quote", 0, "id of the quoted message")
+	if err := parse(fs, args, 2, -1); err != nil {
+		return err
+	}
+
+	chatID, err := parseID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	resp, _, err := a.client.MessageSendContext(ctx, v1.MessageSendRequest{
+		Type:           v1.MsgTypeText,
+		Scope:          scope,
+		ChatID:         chatID,
+		QuoteMessageId: quoteID,
+		Content:        strings.Join(fs.Args()[1:], " "),
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp)
+}
+
+func runMessageEdit(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("messages edit", "MESSAGE_ID TEXT...")
+	if err := parse(fs, args, 2, -1); err != nil {
+		return err
+	}
+
+	messageID, err := parseID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	resp, _, err := a.client.MessageEditContext(ctx, v1.MessageEditRequest{
+		ID:      messageID,
+		Content: strings.Join(fs.Args()[1:], " "),
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp)
+}
+
+func runMessageDelete(ctx context.Context, a *app, args []string) error {
+	fs := a.newFlagSet("messages delete", "MESSAGE_ID")
+	if err := parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	messageID, err := parseID(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	resp, _, err := a.client.MessageDeleteContext(ctx, messageID)
+	if err != nil {
+		return err
+	}
+
+	return a.print(resp)
+}
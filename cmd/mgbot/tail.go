@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+// wsEvents lists events which tail subscribes to by default.
+var wsEvents = []string{
+	v1.WsEventMessageNew,
+	v1.WsEventMessageUpdated,
+	v1.WsEventMessageDeleted,
+	v1.WsEventDialogOpened,
+	v1.WsEventDialogClosed,
+	v1.WsEventDialogAssign,
+	v1.WsEventChatCreated,
+	v1.WsEventChatUpdated,
+	v1.WsEventChatUnreadUpdated,
+	v1.WsEventUserOnlineUpdated,
+	v1.WsEventUserJoined,
+	v1.WsEventUserLeave,
+	v1.WsEventUserUpdated,
+	v1.WsCustomerUpdated,
+	v1.WsBotUpdated,
+	v1.WsEventChannelUpdated,
+	v1.WsEventSettingsUpdated,
+	v1.WsEventChatsDeleted,
+}
+
+// tailer prints received events and writes them to the JSONL file.
+type tailer struct {
+	stdout    io.Writer
+	stderr    io.Writer
+	file      io.Writer
+	raw       bool
+	chatID    uint64
+	channelID uint64
+	msgType   string
+}
+
+func runTail(ctx context.Context, a *app, args []string) error {
+	var (
+		t         = tailer{stdout: a.stdout, stderr: a.stderr}
+		events    string
+		out       string
+		reconnect bool
+	)
+
+	fs := a.newFlagSet("tail", "")
+	fs.StringVar(&events, "events", "", "comma separated event types, e.g. message_new,dialog_assign (default all)")
+	fs.BoolVar(&t.raw, "json", false, "print raw events as JSON lines")
+	fs.Uint64Var(&t.chatID, "chat-id", 0, "only events of the chat")
+	fs.Uint64Var(&t.channelID, "channel-id", 0, "only events of the channel")
+	fs.StringVar(&t.msgType, "type", "", "only messages of the type, e.g. text")
+	fs.StringVar(&out, "out", "", "append raw events to the JSONL file")
+	fs.BoolVar(&reconnect, "reconnect", false, "reconnect when the connection drops")
+	if err := parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	eventTypes := splitList(events)
+	if len(eventTypes) == 0 {
+		eventTypes = wsEvents
+	}
+
+	if out != "" {
+		f, err := os.OpenFile(out, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+		t.file = f
+	}
+
+	var opts []v1.ListenOption
+	if reconnect {
+		opts = append(opts, v1.ListenReconnect(v1.DefaultReconnectPolicy()))
+	}
+
+	fmt.Fprintf(a.stderr, "Listening for %s, press Ctrl+C to stop\n", strings.Join(eventTypes, ", "))
+
+	return a.client.Listen(ctx, eventTypes, t.handle, opts...)
+}
+
+// handle filters, prints and saves the event. Events which cannot be decoded are printed as is.
+func (t *tailer) handle(_ context.Context, event v1.WsEvent) error {
+	data, err := v1.DecodeWsEvent(event)
+	if err != nil && !errors.Is(err, v1.ErrUnknownWsEvent) {
+		fmt.Fprintf(t.stderr, "%s: %v\n", event.Type, err)
+	}
+
+	chatID, channelID, msgType := eventScope(data)
+	if (t.chatID != 0 && t.chatID != chatID) || (t.channelID != 0 && t.channelID != channelID) ||
+		(t.msgType != "" && t.msgType != msgType) {
+		return nil
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	line = append(line, '\n')
+	if t.file != nil {
+		if _, err := t.file.Write(line); err != nil {
+			return err
+		}
+	}
+
+	if t.raw {
+		_, err = t.stdout.Write(line)
+		return err
+	}
+
+	header := v1.NewTimestamp(event.Meta.Time()).String() + " " + event.Type
+	if chatID != 0 {
+		header += fmt.Sprintf(" chat=%d", chatID)
+	}
+
+	if channelID != 0 {
+		header += fmt.Sprintf(" channel=%d", channelID)
+	}
+
+	if msgType != "" {
+		header += " type=" + msgType
+	}
+
+	if event.Recovered {
+		header += " (recovered)"
+	}
+
+	if data == nil {
+		_, err = fmt.Fprintf(t.stdout, "%s\n%s\n", header, event.Data)
+		return err
+	}
+
+	body, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(t.stdout, "%s\n%s\n", header, body)
+
+	return err
+}
+
+// eventScope returns the chat, the channel and the message type of the decoded event.
+// Zero values are returned for the values which the event does not contain.
+func eventScope(data interface{}) (chatID, channelID uint64, msgType string) {
+	switch d := data.(type) {
+	case *v1.WsEventMessageNewData:
+		return messageScope(d.Message)
+	case *v1.WsEventMessageUpdatedData:
+		return messageScope(d.Message)
+	case *v1.WsEventMessageDeletedData:
+		return messageScope(d.Message)
+	case *v1.WsEventDialogOpenedData:
+		return dialogScope(d.Dialog)
+	case *v1.WsEventDialogClosedData:
+		return dialogScope(d.Dialog)
+	case *v1.WsEventDialogAssignData:
+		if d.Chat != nil {
+			chatID, channelID = chatScope(d.Chat)
+			return chatID, channelID, ""
+		}
+
+		return dialogScope(d.Dialog)
+	case *v1.WsEventWaitingChatCreatedData:
+		if d.Chat != nil {
+			chatID, channelID = chatScope(&d.Chat.Chat)
+		}
+	case *v1.WsEventWaitingChatUpdatedData:
+		if d.Chat != nil {
+			chatID, channelID = chatScope(&d.Chat.Chat)
+		}
+	case *v1.EventUserJoinedChatData:
+		chatID, channelID = chatScope(d.Chat)
+	case *v1.WsEventUserLeaveData:
+		chatID = d.Chat.ID
+	case *v1.WsEventChannelUpdatedData:
+		if d.Channel != nil {
+			channelID = d.Channel.ID
+		}
+	}
+
+	return chatID, channelID, ""
+}
+
+func messageScope(message *v1.Message) (chatID, channelID uint64, msgType string) {
+	if message == nil {
+		return 0, 0, ""
+	}
+
+	chatID, channelID = chatScope(message.Chat)
+	if message.ChatID != 0 {
+		chatID = message.ChatID
+	}
+
+	return chatID, channelID, message.Type
+}
+
+func dialogScope(dialog *v1.Dialog) (chatID, channelID uint64, msgType string) {
+	if dialog == nil {
+		return 0, 0, ""
+	}
+
+	chatID, channelID = chatScope(dialog.Chat)
+
+	return chatID, channelID, ""
+}
+
+func chatScope(chat *v1.Chat) (chatID, channelID uint64) {
+	if chat == nil {
+		return 0, 0
+	}
+
+	if chat.Channel != nil {
+		channelID = chat.Channel.ID
+	}
+
+	return chat.ID, channelID
+}
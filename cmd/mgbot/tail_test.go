@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+	"github.com/retailcrm/mg-bot-api-client-go/v1/mgtest"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+func TestRun_Tail(t *testing.T) {
+	srv := mgtest.NewServer()
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "mgbot")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	chat := srv.AddChat(v1.ChatResponseItem{})
+	other := srv.AddChat(v1.ChatResponseItem{})
+	out := filepath.Join(dir, "events.jsonl")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var stdout, stderr syncBuffer
+	done := make(chan error, 1)
+	go func() {
+		done <- run(ctx, []string{
+			"-env", "", "-url", srv.URL, "-token", mgtest.Token,
+			"tail", "-events", "message_new,dialog_assign", "-chat-id", fmt.Sprint(chat.ID), "-out", out,
+		}, noEnv, &stdout, &stderr)
+	}()
+	require.Eventually(t, func() bool { return srv.Listeners() == 1 }, time.Second, 5*time.Millisecond)
+
+	srv.CustomerMessage(other.ID, "Ignored")
+	srv.CustomerMessage(chat.ID, "Where is my order?")
+	srv.Emit(v1.WsEventDialogAssign, v1.WsEventDialogAssignData{Chat: &v1.Chat{ID: chat.ID}})
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(stdout.String(), v1.WsEventDialogAssign)
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Contains(t, stdout.String(), fmt.Sprintf("message_new chat=%d", chat.ID))
+	assert.Contains(t, stdout.String(), "Where is my order?")
+	assert.NotContains(t, stdout.String(), "Ignored")
+
+	data, err := ioutil.ReadFile(out)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var event v1.WsEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &event))
+	decoded, err := v1.DecodeWsEvent(event)
+	require.NoError(t, err)
+	assert.Equal(t, "Where is my order?", decoded.(*v1.WsEventMessageNewData).Message.Content)
+}
+
+func TestTailer_Handle(t *testing.T) {
+	var stdout bytes.Buffer
+	tl := tailer{stdout: &stdout, raw: true, msgType: v1.MsgTypeText}
+
+	for _, msgType := range []string{v1.MsgTypeImage, v1.MsgTypeText} {
+		data, err := json.Marshal(v1.WsEventMessageNewData{Message: &v1.Message{ID: 1, Type: msgType}})
+		require.NoError(t, err)
+		require.NoError(t, tl.handle(context.Background(), v1.WsEvent{Type: v1.WsEventMessageNew, Data: data}))
+	}
+
+	require.NoError(t, tl.handle(context.Background(), v1.WsEvent{Type: v1.WsEventSettingsUpdated}))
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"type":"text"`)
+	assert.NotContains(t, lines[0], `"recovered"`)
+}
+
+func TestTailer_HandleRecovered(t *testing.T) {
+	var stdout bytes.Buffer
+	tl := tailer{stdout: &stdout, raw: true}
+
+	data, err := json.Marshal(v1.WsEventMessageNewData{Message: &v1.Message{ID: 1, Type: v1.MsgTypeText}})
+	require.NoError(t, err)
+	require.NoError(t, tl.handle(context.Background(), v1.WsEvent{
+		Type: v1.WsEventMessageNew, Data: data, Recovered: true,
+	}))
+
+	var event v1.WsEvent
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &event))
+	assert.True(t, event.Recovered)
+	assert.Equal(t, v1.WsEventMessageNew, event.Type)
+}
+
+func TestEventScope(t *testing.T) {
+	chat := &v1.Chat{ID: 12, Channel: &v1.Channel{ID: 3}}
+
+	tests := []struct {
+		data    interface{}
+		chatID  uint64
+		channel uint64
+		msgType string
+	}{
+		{&v1.WsEventMessageNewData{Message: &v1.Message{Type: v1.MsgTypeText, Chat: chat}}, 12, 3, v1.MsgTypeText},
+		{&v1.WsEventMessageDeletedData{Message: &v1.Message{ChatID: 14}}, 14, 0, ""},
+		{&v1.WsEventDialogClosedData{Dialog: &v1.Dialog{Chat: chat}}, 12, 3, ""},
+		{&v1.WsEventDialogAssignData{Chat: chat}, 12, 3, ""},
+		{&v1.WsEventWaitingChatCreatedData{Chat: &v1.WaitingChat{Chat: *chat}}, 12, 3, ""},
+		{&v1.WsEventChannelUpdatedData{Channel: &v1.ChannelResponseItem{ID: 3}}, 0, 3, ""},
+		{&v1.WsEventMessageNewData{}, 0, 0, ""},
+		{nil, 0, 0, ""},
+	}
+
+	for _, test := range tests {
+		chatID, channelID, msgType := eventScope(test.data)
+		assert.Equal(t, test.chatID, chatID)
+		assert.Equal(t, test.channel, channelID)
+		assert.Equal(t, test.msgType, msgType)
+	}
+}
@@ -4,7 +4,9 @@ go 1.13
 
 require (
 	github.com/google/go-querystring v1.1.0
+	github.com/gorilla/websocket v1.5.0
 	github.com/joho/godotenv v1.3.0
 	github.com/stretchr/testify v1.7.0
 	gopkg.in/h2non/gock.v1 v1.1.0
+	gopkg.in/yaml.v3 v3.0.1
 )
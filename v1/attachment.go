@@ -0,0 +1,223 @@
+package v1
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const sniffLen = 512
+
+// AttachmentSource is a file to send with SendAttachment. Exactly one of Reader, Path or URL must be set.
+type AttachmentSource struct {
+	// Reader is the file contents.
+	Reader io.Reader
+	// Path is the local file path.
+	Path string
+	// URL is the file URL, MG downloads the file itself (see UploadFileByURL).
+	URL string
+	// MimeType is detected from the file extension or contents if empty.
+	MimeType string
+	// Name is the uploaded file name, the Path base name is used by default.
+	Name string
+	// Caption is the attachment caption.
+	Caption string
+}
+
+// AttachmentFromReader returns the attachment uploaded from the reader.
+func AttachmentFromReader(reader io.Reader, caption string) AttachmentSource {
+	return AttachmentSource{Reader: reader, Caption: caption}
+}
+
+// AttachmentFromPath returns the attachment uploaded from the local file.
+func AttachmentFromPath(path, caption string) AttachmentSource {
+	return AttachmentSource{Path: path, Caption: caption}
+}
+
+// AttachmentFromURL returns the attachment uploaded by URL.
+func AttachmentFromURL(url, caption string) AttachmentSource {
+	return AttachmentSource{URL: url, Caption: caption}
+}
+
+// SendAttachment uploads the files and sends them to the chat as one public message.
+// See SendAttachmentMessage for details.
+//
+// Example:
+//
+//	data, err := client.SendAttachment(ctx, chatID,
+//		v1.AttachmentFromPath("/tmp/invoice.pdf", "Invoice"),
+//		v1.AttachmentFromURL("https://example.com/receipt.png", "Receipt"),
+//	)
+func (c *MgClient) SendAttachment(
+	ctx context.Context, chatID uint64, files ...AttachmentSource,
+) (MessageSendResponse, error) {
+	return c.SendAttachmentMessage(ctx, MessageSendRequest{ChatID: chatID}, files...)
+}
+
+// SendAttachmentMessage uploads the files and sends them as the message. Request Content is used as the attachments
+// note; Type and Items are set by the method, other fields (Scope, QuoteMessageId, etc.) are sent as is.
+// Scope is public by default.
+//
+// The message type is MsgTypeImage if all files are images, MsgTypeFile otherwise. The message is validated
+// against the chat channel settings (items count, note length, etc.) before uploading the files,
+// see ValidateMessage.
+func (c *MgClient) SendAttachmentMessage(
+	ctx context.Context, request MessageSendRequest, files ...AttachmentSource,
+) (MessageSendResponse, error) {
+	if len(files) == 0 {
+		return MessageSendResponse{}, fmt.Errorf("%w: no attachments", ErrValidation)
+	}
+
+	if request.Scope == "" {
+		request.Scope = MessageScopePublic
+	}
+
+	settings, err := c.ChatChannelSettings(ctx, request.ChatID)
+	if err != nil {
+		return MessageSendResponse{}, err
+	}
+
+	files = append([]AttachmentSource(nil), files...)
+	mimeTypes := make([]string, len(files))
+	for i := range files {
+		if mimeTypes[i], files[i], err = detectAttachmentMime(files[i]); err != nil {
+			return MessageSendResponse{}, err
+		}
+	}
+
+	request.Type = attachmentMessageType(mimeTypes)
+	request.Items = make([]Item, len(files))
+	if err := ValidateMessage(request, settings); err != nil {
+		return MessageSendResponse{}, err
+	}
+
+	for i, file := range files {
+		uploaded, err := c.uploadAttachment(ctx, file)
+		if err != nil {
+			return MessageSendResponse{}, err
+		}
+
+		if uploaded.MimeType != "" && file.MimeType == "" {
+			mimeTypes[i] = uploaded.MimeType
+		}
+
+		request.Items[i] = Item{ID: uploaded.ID, Caption: file.Caption}
+	}
+
+	if msgType := attachmentMessageType(mimeTypes); msgType != request.Type {
+		request.Type = msgType
+		if err := ValidateMessage(request, settings); err != nil {
+			return MessageSendResponse{}, err
+		}
+	}
+
+	resp, _, err := c.MessageSendContext(ctx, request)
+
+	return resp, err
+}
+
+func (c *MgClient) uploadAttachment(ctx context.Context, file AttachmentSource) (UploadFileResponse, error) {
+	if file.URL != "" {
+		resp, _, err := c.UploadFileByURLContext(ctx, UploadFileByUrlRequest{Url: file.URL})
+		return resp, err
+	}
+
+	var opts []UploadOption
+	if file.MimeType != "" {
+		opts = append(opts, UploadContentType(file.MimeType))
+	}
+
+	if file.Name != "" {
+		opts = append(opts, UploadFileName(file.Name))
+	}
+
+	if file.Path != "" {
+		resp, _, err := c.UploadFilePath(ctx, file.Path, opts...)
+		return resp, err
+	}
+
+	resp, _, err := c.UploadFileStream(ctx, file.Reader, opts...)
+
+	return resp, err
+}
+
+// detectAttachmentMime returns MIME type of the attachment. The reader of the returned source must be used
+// instead of the original one because it may be partially read.
+func detectAttachmentMime(file AttachmentSource) (string, AttachmentSource, error) {
+	var set int
+	for _, value := range []bool{file.Reader != nil, file.Path != "", file.URL != ""} {
+		if value {
+			set++
+		}
+	}
+
+	if set != 1 {
+		return "", file, fmt.Errorf("%w: exactly one of attachment Reader, Path or URL must be set", ErrValidation)
+	}
+
+	if file.MimeType != "" {
+		return file.MimeType, file, nil
+	}
+
+	switch {
+	case file.URL != "":
+		u, err := url.Parse(file.URL)
+		if err != nil {
+			return "", file, err
+		}
+
+		return mime.TypeByExtension(path.Ext(u.Path)), file, nil
+	case file.Path != "":
+		if mimeType := mime.TypeByExtension(filepath.Ext(file.Path)); mimeType != "" {
+			return mimeType, file, nil
+		}
+
+		f, err := os.Open(file.Path)
+		if err != nil {
+			return "", file, err
+		}
+		defer f.Close()
+
+		return sniffMime(f), file, nil
+	default:
+		reader := bufio.NewReaderSize(file.Reader, sniffLen)
+		file.Reader = reader
+
+		return sniffMime(reader), file, nil
+	}
+}
+
+func sniffMime(reader io.Reader) string {
+	if br, ok := reader.(*bufio.Reader); ok {
+		head, err := br.Peek(sniffLen)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+			return ""
+		}
+
+		return http.DetectContentType(head)
+	}
+
+	head := make([]byte, sniffLen)
+	n, _ := io.ReadFull(reader, head)
+
+	return http.DetectContentType(head[:n])
+}
+
+func attachmentMessageType(mimeTypes []string) string {
+	for _, mimeType := range mimeTypes {
+		if !strings.HasPrefix(mimeType, "image/") {
+			return MsgTypeFile
+		}
+	}
+
+	return MsgTypeImage
+}
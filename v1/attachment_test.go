@@ -0,0 +1,189 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+// mockChatChannel mocks the chat and its channel with the given settings.
+func mockChatChannel(chatID uint64, settings ChannelSettings) {
+	channelID := chatID + 100
+
+	gock.New(mgURL).
+		Get("/api/bot/v1/chats").
+		MatchParam("id", strconv.FormatUint(chatID, 10)).
+		Reply(200).
+		JSON([]ChatResponseItem{{ID: chatID, Channel: Channel{ID: channelID}}})
+
+	gock.New(mgURL).
+		Get("/api/bot/v1/channels").
+		MatchParam("id", strconv.FormatUint(channelID, 10)).
+		Reply(200).
+		JSON([]ChannelResponseItem{{ID: channelID, Type: "telegram", Settings: settings, IsActive: true}})
+}
+
+// matchUploadBody matches the uploaded file contents. The body is read, so the upload progress is reported.
+func matchUploadBody(data []byte) gock.MatchFunc {
+	return func(req *http.Request, _ *gock.Request) (bool, error) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			return false, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		return bytes.Equal(body, data), nil
+	}
+}
+
+// sentMessage returns the last message sent by the client.
+func sentMessage(t *testing.T, requests *requestRecorder) MessageSendRequest {
+	var message MessageSendRequest
+	for _, req := range requests.all() {
+		if req.Method == http.MethodPost && req.Path == "/messages" {
+			require.NoError(t, json.Unmarshal(req.Body, &message))
+		}
+	}
+
+	return message
+}
+
+func TestMgClient_SendAttachment(t *testing.T) {
+	defer gock.Off()
+
+	var settings ChannelSettings
+	settings.File.MaxItemsCount = 10
+	mockChatChannel(1, settings)
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/files/upload").
+		MatchHeader("Content-Type", "application/pdf").
+		AddMatcher(matchUploadBody([]byte("%PDF-1.4"))).
+		Reply(200).
+		JSON(UploadFileResponse{ID: "invoice", MimeType: "application/pdf"})
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/files/upload").
+		MatchHeader("Content-Type", "image/png").
+		AddMatcher(matchUploadBody(pngHeader)).
+		Reply(200).
+		JSON(UploadFileResponse{ID: "photo", MimeType: "image/png"})
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/files/upload_by_url").
+		JSON(UploadFileByUrlRequest{Url: "https://example.com/receipt"}).
+		Reply(200).
+		JSON(UploadFileResponse{ID: "receipt", MimeType: "text/plain"})
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/messages").
+		Reply(200).
+		BodyString(`{"message_id": 10, "time": "2024-01-31T10:00:00Z"}`)
+
+	dir, err := ioutil.TempDir("", "attachment")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "invoice.pdf")
+	require.NoError(t, ioutil.WriteFile(path, []byte("%PDF-1.4"), 0600))
+
+	var requests requestRecorder
+	resp, err := client(requests.option()).SendAttachment(context.Background(), 1,
+		AttachmentFromPath(path, "Invoice"),
+		AttachmentFromReader(bytes.NewReader(pngHeader), "Photo"),
+		AttachmentFromURL("https://example.com/receipt", "Receipt"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), resp.MessageID)
+	assert.True(t, gock.IsDone())
+
+	message := sentMessage(t, &requests)
+	assert.Equal(t, uint64(1), message.ChatID)
+	assert.Equal(t, MsgTypeFile, message.Type)
+	assert.Equal(t, MessageScopePublic, message.Scope)
+	assert.Equal(t, []Item{
+		{ID: "invoice", Caption: "Invoice"},
+		{ID: "photo", Caption: "Photo"},
+		{ID: "receipt", Caption: "Receipt"},
+	}, message.Items)
+}
+
+func TestMgClient_SendAttachmentImages(t *testing.T) {
+	defer gock.Off()
+
+	mockChatChannel(1, ChannelSettings{})
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/files/upload").
+		AddMatcher(matchUploadBody(pngHeader)).
+		Reply(200).
+		JSON(UploadFileResponse{ID: "photo", MimeType: "image/png"})
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/files/upload_by_url").
+		Reply(200).
+		JSON(UploadFileResponse{ID: "remote", MimeType: "image/png"})
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/messages").
+		Reply(200).
+		BodyString(`{"message_id": 10, "time": "2024-01-31T10:00:00Z"}`)
+
+	var requests requestRecorder
+	_, err := client(requests.option()).SendAttachmentMessage(context.Background(),
+		MessageSendRequest{ChatID: 1, Content: "Photos"},
+		AttachmentFromReader(bytes.NewReader(pngHeader), ""),
+		AttachmentFromURL("https://example.com/photo.png", ""),
+	)
+	require.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	message := sentMessage(t, &requests)
+	assert.Equal(t, MsgTypeImage, message.Type)
+	assert.Equal(t, "Photos", message.Content)
+	assert.Len(t, message.Items, 2)
+}
+
+func TestMgClient_SendAttachmentTooManyItems(t *testing.T) {
+	defer gock.Off()
+
+	var settings ChannelSettings
+	settings.Image.MaxItemsCount = 1
+	mockChatChannel(1, settings)
+
+	var requests requestRecorder
+	_, err := client(requests.option()).SendAttachment(context.Background(), 1,
+		AttachmentFromReader(strings.NewReader(string(pngHeader)), ""),
+		AttachmentSource{URL: "https://example.com/photo.jpg"},
+	)
+
+	var validationErr *MessageValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.True(t, validationErr.Has(ViolationTooManyItems))
+	assert.Equal(t, []string{"GET /chats", "GET /channels"}, requests.paths(), "files must not be uploaded")
+}
+
+func TestMgClient_SendAttachmentInvalidSource(t *testing.T) {
+	defer gock.Off()
+
+	mockChatChannel(1, ChannelSettings{})
+
+	var requests requestRecorder
+	_, err := client(requests.option()).SendAttachment(context.Background(), 1, AttachmentSource{Caption: "empty"})
+	assert.True(t, IsValidation(err))
+	assert.Equal(t, []string{"GET /chats", "GET /channels"}, requests.paths(), "nothing must be sent")
+}
@@ -2,6 +2,7 @@ package v1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -89,17 +90,26 @@ func (c *MgClient) writeLog(format string, v ...interface{}) {
 // 		fmt.Printf("%v %v\n", bot.Name, bot.CreatedAt)
 // 	}
 func (c *MgClient) Bots(request BotsRequest) ([]BotsResponseItem, int, error) {
+	return c.BotsContext(context.Background(), request)
+}
+
+// BotsContext is like Bots but uses the provided context.
+func (c *MgClient) BotsContext(ctx context.Context, request BotsRequest) ([]BotsResponseItem, int, error) {
 	var resp []BotsResponseItem
 	var b []byte
+	if err := prepareTimeFilter(&request.Since, &request.Until); err != nil {
+		return resp, 0, err
+	}
+
 	outgoing, _ := query.Values(request)
 
-	data, status, err := c.GetRequest(fmt.Sprintf("/bots?%s", outgoing.Encode()), b)
+	data, status, err := c.GetRequestContext(ctx, fmt.Sprintf("/bots?%s", outgoing.Encode()), b)
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodGet, "/bots", status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -129,17 +139,26 @@ func (c *MgClient) Bots(request BotsRequest) ([]BotsResponseItem, int, error) {
 // 		fmt.Printf("%v %v\n", channel.Type, channel.CreatedAt)
 // 	}
 func (c *MgClient) Channels(request ChannelsRequest) ([]ChannelResponseItem, int, error) {
+	return c.ChannelsContext(context.Background(), request)
+}
+
+// ChannelsContext is like Channels but uses the provided context.
+func (c *MgClient) ChannelsContext(ctx context.Context, request ChannelsRequest) ([]ChannelResponseItem, int, error) {
 	var resp []ChannelResponseItem
 	var b []byte
+	if err := prepareTimeFilter(&request.Since, &request.Until); err != nil {
+		return resp, 0, err
+	}
+
 	outgoing, _ := query.Values(request)
 
-	data, status, err := c.GetRequest(fmt.Sprintf("/channels?%s", outgoing.Encode()), b)
+	data, status, err := c.GetRequestContext(ctx, fmt.Sprintf("/channels?%s", outgoing.Encode()), b)
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodGet, "/channels", status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -169,17 +188,26 @@ func (c *MgClient) Channels(request ChannelsRequest) ([]ChannelResponseItem, int
 // 		fmt.Printf("%v %v\n", user.FirstName, user.IsOnline)
 // 	}
 func (c *MgClient) Users(request UsersRequest) ([]UsersResponseItem, int, error) {
+	return c.UsersContext(context.Background(), request)
+}
+
+// UsersContext is like Users but uses the provided context.
+func (c *MgClient) UsersContext(ctx context.Context, request UsersRequest) ([]UsersResponseItem, int, error) {
 	var resp []UsersResponseItem
 	var b []byte
+	if err := prepareTimeFilter(&request.Since, &request.Until); err != nil {
+		return resp, 0, err
+	}
+
 	outgoing, _ := query.Values(request)
 
-	data, status, err := c.GetRequest(fmt.Sprintf("/users?%s", outgoing.Encode()), b)
+	data, status, err := c.GetRequestContext(ctx, fmt.Sprintf("/users?%s", outgoing.Encode()), b)
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodGet, "/users", status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -209,17 +237,28 @@ func (c *MgClient) Users(request UsersRequest) ([]UsersResponseItem, int, error)
 // 		fmt.Printf("%v %v\n", customer.FirstName, customer.Avatar)
 // 	}
 func (c *MgClient) Customers(request CustomersRequest) ([]CustomersResponseItem, int, error) {
+	return c.CustomersContext(context.Background(), request)
+}
+
+// CustomersContext is like Customers but uses the provided context.
+func (c *MgClient) CustomersContext(
+	ctx context.Context, request CustomersRequest,
+) ([]CustomersResponseItem, int, error) {
 	var resp []CustomersResponseItem
 	var b []byte
+	if err := prepareTimeFilter(&request.Since, &request.Until); err != nil {
+		return resp, 0, err
+	}
+
 	outgoing, _ := query.Values(request)
 
-	data, status, err := c.GetRequest(fmt.Sprintf("/customers?%s", outgoing.Encode()), b)
+	data, status, err := c.GetRequestContext(ctx, fmt.Sprintf("/customers?%s", outgoing.Encode()), b)
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodGet, "/customers", status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -249,17 +288,26 @@ func (c *MgClient) Customers(request CustomersRequest) ([]CustomersResponseItem,
 // 		fmt.Printf("%v %v\n", chat.Customer, chat.LastMessage)
 // 	}
 func (c *MgClient) Chats(request ChatsRequest) ([]ChatResponseItem, int, error) {
+	return c.ChatsContext(context.Background(), request)
+}
+
+// ChatsContext is like Chats but uses the provided context.
+func (c *MgClient) ChatsContext(ctx context.Context, request ChatsRequest) ([]ChatResponseItem, int, error) {
 	var resp []ChatResponseItem
 	var b []byte
+	if err := prepareTimeFilter(&request.Since, &request.Until); err != nil {
+		return resp, 0, err
+	}
+
 	outgoing, _ := query.Values(request)
 
-	data, status, err := c.GetRequest(fmt.Sprintf("/chats?%s", outgoing.Encode()), b)
+	data, status, err := c.GetRequestContext(ctx, fmt.Sprintf("/chats?%s", outgoing.Encode()), b)
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodGet, "/chats", status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -289,17 +337,26 @@ func (c *MgClient) Chats(request ChatsRequest) ([]ChatResponseItem, int, error)
 // 		fmt.Printf("%v\n", member.CreatedAt)
 // 	}
 func (c *MgClient) Members(request MembersRequest) ([]MemberResponseItem, int, error) {
+	return c.MembersContext(context.Background(), request)
+}
+
+// MembersContext is like Members but uses the provided context.
+func (c *MgClient) MembersContext(ctx context.Context, request MembersRequest) ([]MemberResponseItem, int, error) {
 	var resp []MemberResponseItem
 	var b []byte
+	if err := prepareTimeFilter(&request.Since, &request.Until); err != nil {
+		return resp, 0, err
+	}
+
 	outgoing, _ := query.Values(request)
 
-	data, status, err := c.GetRequest(fmt.Sprintf("/members?%s", outgoing.Encode()), b)
+	data, status, err := c.GetRequestContext(ctx, fmt.Sprintf("/members?%s", outgoing.Encode()), b)
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodGet, "/members", status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -329,17 +386,26 @@ func (c *MgClient) Members(request MembersRequest) ([]MemberResponseItem, int, e
 // 		fmt.Printf("%v %v\n", dialog.ChatID, dialog.CreatedAt)
 // 	}
 func (c *MgClient) Dialogs(request DialogsRequest) ([]DialogResponseItem, int, error) {
+	return c.DialogsContext(context.Background(), request)
+}
+
+// DialogsContext is like Dialogs but uses the provided context.
+func (c *MgClient) DialogsContext(ctx context.Context, request DialogsRequest) ([]DialogResponseItem, int, error) {
 	var resp []DialogResponseItem
 	var b []byte
+	if err := prepareTimeFilter(&request.Since, &request.Until); err != nil {
+		return resp, 0, err
+	}
+
 	outgoing, _ := query.Values(request)
 
-	data, status, err := c.GetRequest(fmt.Sprintf("/dialogs?%s", outgoing.Encode()), b)
+	data, status, err := c.GetRequestContext(ctx, fmt.Sprintf("/dialogs?%s", outgoing.Encode()), b)
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodGet, "/dialogs", status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -367,16 +433,24 @@ func (c *MgClient) Dialogs(request DialogsRequest) ([]DialogResponseItem, int, e
 //
 // 	fmt.Printf("%v %v\n", data.Responsible, data.LeftUserID )
 func (c *MgClient) DialogAssign(request DialogAssignRequest) (DialogAssignResponse, int, error) {
+	return c.DialogAssignContext(context.Background(), request)
+}
+
+// DialogAssignContext is like DialogAssign but uses the provided context.
+func (c *MgClient) DialogAssignContext(
+	ctx context.Context, request DialogAssignRequest,
+) (DialogAssignResponse, int, error) {
 	var resp DialogAssignResponse
 	outgoing, _ := json.Marshal(&request)
 
-	data, status, err := c.PatchRequest(fmt.Sprintf("/dialogs/%d/assign", request.DialogID), []byte(outgoing))
+	path := fmt.Sprintf("/dialogs/%d/assign", request.DialogID)
+	data, status, err := c.PatchRequestContext(ctx, path, []byte(outgoing))
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodPatch, path, status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -404,15 +478,21 @@ func (c *MgClient) DialogAssign(request DialogAssignRequest) (DialogAssignRespon
 //
 // 	fmt.Printf("%v\n", data.Responsible)
 func (c *MgClient) DialogUnassign(dialogID uint64) (DialogUnassignResponse, int, error) {
+	return c.DialogUnassignContext(context.Background(), dialogID)
+}
+
+// DialogUnassignContext is like DialogUnassign but uses the provided context.
+func (c *MgClient) DialogUnassignContext(ctx context.Context, dialogID uint64) (DialogUnassignResponse, int, error) {
 	var resp DialogUnassignResponse
 
-	data, status, err := c.PatchRequest(fmt.Sprintf("/dialogs/%d/unassign", dialogID), nil)
+	path := fmt.Sprintf("/dialogs/%d/unassign", dialogID)
+	data, status, err := c.PatchRequestContext(ctx, path, nil)
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status != http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodPatch, path, status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -438,16 +518,22 @@ func (c *MgClient) DialogUnassign(dialogID uint64) (DialogUnassignResponse, int,
 // 		fmt.Printf("%v", err)
 // 	}
 func (c *MgClient) DialogClose(request uint64) (map[string]interface{}, int, error) {
+	return c.DialogCloseContext(context.Background(), request)
+}
+
+// DialogCloseContext is like DialogClose but uses the provided context.
+func (c *MgClient) DialogCloseContext(ctx context.Context, request uint64) (map[string]interface{}, int, error) {
 	var resp map[string]interface{}
 	outgoing, _ := json.Marshal(&request)
 
-	data, status, err := c.DeleteRequest(fmt.Sprintf("/dialogs/%d/close", request), []byte(outgoing))
+	path := fmt.Sprintf("/dialogs/%d/close", request)
+	data, status, err := c.DeleteRequestContext(ctx, path, []byte(outgoing))
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodDelete, path, status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -465,15 +551,21 @@ func (c *MgClient) DialogClose(request uint64) (map[string]interface{}, int, err
 //
 //	data, status, err := client.DialogsTagsAdd(DialogTagsAddRequest{DialogID: uint64(1),Tags: []TagsAdd{{Name: "foo"}}})
 func (c *MgClient) DialogsTagsAdd(request DialogTagsAddRequest) (int, error) {
+	return c.DialogsTagsAddContext(context.Background(), request)
+}
+
+// DialogsTagsAddContext is like DialogsTagsAdd but uses the provided context.
+func (c *MgClient) DialogsTagsAddContext(ctx context.Context, request DialogTagsAddRequest) (int, error) {
 	outgoing, _ := json.Marshal(&request)
 
-	data, status, err := c.PatchRequest(fmt.Sprintf("/dialogs/%d/tags/add", request.DialogID), outgoing)
+	path := fmt.Sprintf("/dialogs/%d/tags/add", request.DialogID)
+	data, status, err := c.PatchRequestContext(ctx, path, outgoing)
 	if err != nil {
 		return status, err
 	}
 
 	if status != http.StatusOK {
-		return status, c.Error(data)
+		return status, newAPIError(http.MethodPatch, path, status, data)
 	}
 
 	return status, err
@@ -487,15 +579,21 @@ func (c *MgClient) DialogsTagsAdd(request DialogTagsAddRequest) (int, error) {
 //
 //	data, status, err := client.DialogsTagsAdd(DialogTagsDelete{DialogID: uint64(1),Tags: []TagsDelete{{Name: "foo"}}})
 func (c *MgClient) DialogTagsDelete(request DialogTagsDeleteRequest) (int, error) {
+	return c.DialogTagsDeleteContext(context.Background(), request)
+}
+
+// DialogTagsDeleteContext is like DialogTagsDelete but uses the provided context.
+func (c *MgClient) DialogTagsDeleteContext(ctx context.Context, request DialogTagsDeleteRequest) (int, error) {
 	outgoing, _ := json.Marshal(&request)
 
-	data, status, err := c.PatchRequest(fmt.Sprintf("/dialogs/%d/tags/delete", request.DialogID), outgoing)
+	path := fmt.Sprintf("/dialogs/%d/tags/delete", request.DialogID)
+	data, status, err := c.PatchRequestContext(ctx, path, outgoing)
 	if err != nil {
 		return status, err
 	}
 
 	if status != http.StatusOK {
-		return status, c.Error(data)
+		return status, newAPIError(http.MethodPatch, path, status, data)
 	}
 
 	return status, err
@@ -521,17 +619,26 @@ func (c *MgClient) DialogTagsDelete(request DialogTagsDeleteRequest) (int, error
 // 		fmt.Printf("%v %v %v\n", message.ChatID, message.CreatedAt, message.CustomerID)
 // 	}
 func (c *MgClient) Messages(request MessagesRequest) ([]MessagesResponseItem, int, error) {
+	return c.MessagesContext(context.Background(), request)
+}
+
+// MessagesContext is like Messages but uses the provided context.
+func (c *MgClient) MessagesContext(ctx context.Context, request MessagesRequest) ([]MessagesResponseItem, int, error) {
 	var resp []MessagesResponseItem
 	var b []byte
+	if err := prepareTimeFilter(&request.Since, &request.Until); err != nil {
+		return resp, 0, err
+	}
+
 	outgoing, _ := query.Values(request)
 
-	data, status, err := c.GetRequest(fmt.Sprintf("/messages?%s", outgoing.Encode()), b)
+	data, status, err := c.GetRequestContext(ctx, fmt.Sprintf("/messages?%s", outgoing.Encode()), b)
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodGet, "/messages", status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -563,16 +670,23 @@ func (c *MgClient) Messages(request MessagesRequest) ([]MessagesResponseItem, in
 //
 // fmt.Printf("%v \n", data.MessageID, data.Time)
 func (c *MgClient) MessageSend(request MessageSendRequest) (MessageSendResponse, int, error) {
+	return c.MessageSendContext(context.Background(), request)
+}
+
+// MessageSendContext is like MessageSend but uses the provided context.
+func (c *MgClient) MessageSendContext(
+	ctx context.Context, request MessageSendRequest,
+) (MessageSendResponse, int, error) {
 	var resp MessageSendResponse
 	outgoing, _ := json.Marshal(&request)
 
-	data, status, err := c.PostRequest("/messages", bytes.NewBuffer(outgoing))
+	data, status, err := c.PostRequestContext(ctx, "/messages", bytes.NewBuffer(outgoing))
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodPost, "/messages", status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -601,16 +715,24 @@ func (c *MgClient) MessageSend(request MessageSendRequest) (MessageSendResponse,
 // 		fmt.Printf("%v", err)
 // 	}
 func (c *MgClient) MessageEdit(request MessageEditRequest) (map[string]interface{}, int, error) {
+	return c.MessageEditContext(context.Background(), request)
+}
+
+// MessageEditContext is like MessageEdit but uses the provided context.
+func (c *MgClient) MessageEditContext(
+	ctx context.Context, request MessageEditRequest,
+) (map[string]interface{}, int, error) {
 	var resp map[string]interface{}
 	outgoing, _ := json.Marshal(&request)
 
-	data, status, err := c.PatchRequest(fmt.Sprintf("/messages/%d", request.ID), []byte(outgoing))
+	path := fmt.Sprintf("/messages/%d", request.ID)
+	data, status, err := c.PatchRequestContext(ctx, path, []byte(outgoing))
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodPatch, path, status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -636,16 +758,22 @@ func (c *MgClient) MessageEdit(request MessageEditRequest) (map[string]interface
 // 		fmt.Printf("%v", err)
 // 	}
 func (c *MgClient) MessageDelete(request uint64) (map[string]interface{}, int, error) {
+	return c.MessageDeleteContext(context.Background(), request)
+}
+
+// MessageDeleteContext is like MessageDelete but uses the provided context.
+func (c *MgClient) MessageDeleteContext(ctx context.Context, request uint64) (map[string]interface{}, int, error) {
 	var resp map[string]interface{}
 	outgoing, _ := json.Marshal(&request)
 
-	data, status, err := c.DeleteRequest(fmt.Sprintf("/messages/%d", request), []byte(outgoing))
+	path := fmt.Sprintf("/messages/%d", request)
+	data, status, err := c.DeleteRequestContext(ctx, path, []byte(outgoing))
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodDelete, path, status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -671,16 +799,21 @@ func (c *MgClient) MessageDelete(request uint64) (map[string]interface{}, int, e
 // 		fmt.Printf("%v", err)
 // 	}
 func (c *MgClient) Info(request InfoRequest) (map[string]interface{}, int, error) {
+	return c.InfoContext(context.Background(), request)
+}
+
+// InfoContext is like Info but uses the provided context.
+func (c *MgClient) InfoContext(ctx context.Context, request InfoRequest) (map[string]interface{}, int, error) {
 	var resp map[string]interface{}
 	outgoing, _ := json.Marshal(&request)
 
-	data, status, err := c.PatchRequest("/my/info", []byte(outgoing))
+	data, status, err := c.PatchRequestContext(ctx, "/my/info", []byte(outgoing))
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodPatch, "/my/info", status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -710,17 +843,26 @@ func (c *MgClient) Info(request InfoRequest) (map[string]interface{}, int, error
 // 		fmt.Printf("%v %v\n", command.Name, command.Description)
 // 	}
 func (c *MgClient) Commands(request CommandsRequest) ([]CommandsResponseItem, int, error) {
+	return c.CommandsContext(context.Background(), request)
+}
+
+// CommandsContext is like Commands but uses the provided context.
+func (c *MgClient) CommandsContext(ctx context.Context, request CommandsRequest) ([]CommandsResponseItem, int, error) {
 	var resp []CommandsResponseItem
 	var b []byte
+	if err := prepareTimeFilter(&request.Since, &request.Until); err != nil {
+		return resp, 0, err
+	}
+
 	outgoing, _ := query.Values(request)
 
-	data, status, err := c.GetRequest(fmt.Sprintf("/my/commands?%s", outgoing.Encode()), b)
+	data, status, err := c.GetRequestContext(ctx, fmt.Sprintf("/my/commands?%s", outgoing.Encode()), b)
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodGet, "/my/commands", status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -752,16 +894,24 @@ func (c *MgClient) Commands(request CommandsRequest) ([]CommandsResponseItem, in
 //
 // 	fmt.Printf("%v %v\n", data.Name, data.Description)
 func (c *MgClient) CommandEdit(request CommandEditRequest) (CommandsResponseItem, int, error) {
+	return c.CommandEditContext(context.Background(), request)
+}
+
+// CommandEditContext is like CommandEdit but uses the provided context.
+func (c *MgClient) CommandEditContext(
+	ctx context.Context, request CommandEditRequest,
+) (CommandsResponseItem, int, error) {
 	var resp CommandsResponseItem
 	outgoing, _ := json.Marshal(&request)
 
-	data, status, err := c.PutRequest(fmt.Sprintf("/my/commands/%s", request.Name), []byte(outgoing))
+	path := fmt.Sprintf("/my/commands/%s", request.Name)
+	data, status, err := c.PutRequestContext(ctx, path, []byte(outgoing))
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodPut, path, status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -787,16 +937,22 @@ func (c *MgClient) CommandEdit(request CommandEditRequest) (CommandsResponseItem
 // 		fmt.Printf("%v", err)
 // 	}
 func (c *MgClient) CommandDelete(request string) (map[string]interface{}, int, error) {
+	return c.CommandDeleteContext(context.Background(), request)
+}
+
+// CommandDeleteContext is like CommandDelete but uses the provided context.
+func (c *MgClient) CommandDeleteContext(ctx context.Context, request string) (map[string]interface{}, int, error) {
 	var resp map[string]interface{}
 	outgoing, _ := json.Marshal(&request)
 
-	data, status, err := c.DeleteRequest(fmt.Sprintf("/my/commands/%s", request), []byte(outgoing))
+	path := fmt.Sprintf("/my/commands/%s", request)
+	data, status, err := c.DeleteRequestContext(ctx, path, []byte(outgoing))
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status > http.StatusCreated || status < http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodDelete, path, status, data)
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
@@ -820,17 +976,23 @@ func (c *MgClient) CommandDelete(request string) (map[string]interface{}, int, e
 //
 //	fmt.Printf("%s\n", data.ID)
 func (c *MgClient) GetFile(request string) (FullFileResponse, int, error) {
+	return c.GetFileContext(context.Background(), request)
+}
+
+// GetFileContext is like GetFile but uses the provided context.
+func (c *MgClient) GetFileContext(ctx context.Context, request string) (FullFileResponse, int, error) {
 	var resp FullFileResponse
 	var b []byte
 
-	data, status, err := c.GetRequest(fmt.Sprintf("/files/%s", request), b)
+	path := fmt.Sprintf("/files/%s", request)
+	data, status, err := c.GetRequestContext(ctx, path, b)
 
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status != http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodGet, path, status, data)
 	}
 
 	if e := json.Unmarshal(data, &resp); e != nil {
@@ -861,22 +1023,13 @@ func (c *MgClient) GetFile(request string) (FullFileResponse, int, error) {
 //
 //	fmt.Printf("%s\n%s", data.ID, status)
 func (c *MgClient) UploadFile(request io.Reader) (UploadFileResponse, int, error) {
-	var resp UploadFileResponse
-
-	data, status, err := c.PostRequest("/files/upload", request)
-	if err != nil {
-		return resp, status, err
-	}
-
-	if status != http.StatusOK {
-		return resp, status, c.Error(data)
-	}
-
-	if e := json.Unmarshal(data, &resp); e != nil {
-		return resp, status, e
-	}
+	return c.UploadFileContext(context.Background(), request)
+}
 
-	return resp, status, err
+// UploadFileContext is like UploadFile but uses the provided context.
+// The file is streamed with the detected content type, see UploadFileStream for more options.
+func (c *MgClient) UploadFileContext(ctx context.Context, request io.Reader) (UploadFileResponse, int, error) {
+	return c.UploadFileStream(ctx, request)
 }
 
 // UploadFileByURL upload file by url
@@ -893,16 +1046,23 @@ func (c *MgClient) UploadFile(request io.Reader) (UploadFileResponse, int, error
 //
 //	fmt.Printf("%s\n%s", uploadFileResponse.ID, status)
 func (c *MgClient) UploadFileByURL(request UploadFileByUrlRequest) (UploadFileResponse, int, error) {
+	return c.UploadFileByURLContext(context.Background(), request)
+}
+
+// UploadFileByURLContext is like UploadFileByURL but uses the provided context.
+func (c *MgClient) UploadFileByURLContext(
+	ctx context.Context, request UploadFileByUrlRequest,
+) (UploadFileResponse, int, error) {
 	var resp UploadFileResponse
 	outgoing, _ := json.Marshal(&request)
 
-	data, status, err := c.PostRequest("/files/upload_by_url", bytes.NewBuffer(outgoing))
+	data, status, err := c.PostRequestContext(ctx, "/files/upload_by_url", bytes.NewBuffer(outgoing))
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status != http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodPost, "/files/upload_by_url", status, data)
 	}
 
 	if e := json.Unmarshal(data, &resp); e != nil {
@@ -927,19 +1087,27 @@ func (c *MgClient) UploadFileByURL(request UploadFileByUrlRequest) (UploadFileRe
 //
 //	fmt.Printf("%s\n%s", response.ID, status)
 func (c *MgClient) UpdateFileMetadata(request UpdateFileMetadataRequest) (UploadFileResponse, int, error) {
+	return c.UpdateFileMetadataContext(context.Background(), request)
+}
+
+// UpdateFileMetadataContext is like UpdateFileMetadata but uses the provided context.
+func (c *MgClient) UpdateFileMetadataContext(
+	ctx context.Context, request UpdateFileMetadataRequest,
+) (UploadFileResponse, int, error) {
 	var resp UploadFileResponse
 	outgoing, err := json.Marshal(&request)
 	if err != nil {
 		return resp, 0, err
 	}
 
-	data, status, err := c.PutRequest(fmt.Sprintf("/files/%s/meta", request.ID), outgoing)
+	path := fmt.Sprintf("/files/%s/meta", request.ID)
+	data, status, err := c.PutRequestContext(ctx, path, outgoing)
 	if err != nil {
 		return resp, status, err
 	}
 
 	if status != http.StatusOK {
-		return resp, status, c.Error(data)
+		return resp, status, newAPIError(http.MethodPut, path, status, data)
 	}
 
 	if e := json.Unmarshal(data, &resp); e != nil {
@@ -970,7 +1138,7 @@ func (c *MgClient) WsMeta(events []string, urlParams ...WsParams) (string, http.
 		return url, nil, err
 	}
 
-	url = fmt.Sprintf("%s%s%s%s", strings.Replace(c.URL, "https", "wss", 1), prefix, "/ws?events=", strings.Join(events[:], ","))
+	url = fmt.Sprintf("%s%s%s%s", wsBaseURL(c.URL), prefix, "/ws?events=", strings.Join(events[:], ","))
 
 	var params wsParams
 	for _, param := range urlParams {
@@ -991,14 +1159,17 @@ func (c *MgClient) WsMeta(events []string, urlParams ...WsParams) (string, http.
 	return url, headers, nil
 }
 
-func (c *MgClient) Error(info []byte) error {
-	var data map[string]interface{}
-
-	if err := json.Unmarshal(info, &data); err != nil {
-		return err
+// wsBaseURL replaces http(s) scheme of the API URL with ws(s).
+func wsBaseURL(url string) string {
+	if strings.HasPrefix(url, "http://") {
+		return "ws://" + strings.TrimPrefix(url, "http://")
 	}
 
-	values := data["errors"].([]interface{})
+	return strings.Replace(url, "https", "wss", 1)
+}
 
-	return errors.New(values[0].(string))
+// Error parses MG Bot API error response body into *APIError.
+// Unlike errors returned by the client methods, it has no status code and endpoint.
+func (c *MgClient) Error(info []byte) error {
+	return newAPIError("", "", 0, info)
 }
@@ -3,14 +3,17 @@ package v1
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/require"
 	"io"
 	"log"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
 	"strings"
@@ -559,6 +562,53 @@ func TestMgClient_Messages(t *testing.T) {
 	}
 }
 
+func TestMgClient_MessagesTimeFilter(t *testing.T) {
+	c := client()
+
+	defer gock.Off()
+
+	gock.New(mgURL).
+		Get("/api/bot/v1/messages").
+		MatchParam("since", "2021-03-04T10:20:30Z").
+		MatchParam("until", "2021-03-05T10:20:30Z").
+		Reply(200).
+		BodyString(`[]`)
+
+	_, status, err := c.Messages(MessagesRequest{
+		Since: time.Date(2021, 3, 4, 10, 20, 30, 0, time.UTC),
+		Until: time.Date(2021, 3, 5, 13, 20, 30, 0, time.FixedZone("MSK", 3*60*60)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.True(t, gock.IsDone())
+}
+
+func TestMgClient_TimeFilterValidation(t *testing.T) {
+	c := client()
+	now := time.Now()
+
+	_, _, err := c.Messages(MessagesRequest{Since: now.Add(-time.Hour), Until: now.Add(-2 * time.Hour)})
+	assert.True(t, IsValidation(err))
+
+	_, _, err = c.Dialogs(DialogsRequest{Since: now.Add(-time.Hour), Until: now.Add(-time.Hour)})
+	assert.True(t, IsValidation(err))
+
+	_, _, err = c.Chats(ChatsRequest{Since: now.Add(time.Hour)})
+	assert.True(t, IsValidation(err))
+
+	// Open-ended periods with Until in the future are allowed.
+	defer gock.Off()
+
+	gock.New(mgURL).
+		Get("/api/bot/v1/bots").
+		Reply(200).
+		BodyString(`[]`)
+
+	_, _, err = c.Bots(BotsRequest{Since: now.Add(-time.Hour), Until: now.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
 func TestMgClient_MessagesDialog(t *testing.T) {
 	t.Parallel()
 
@@ -716,11 +766,11 @@ func TestMgClient_MessageSendProduct(t *testing.T) {
 			Url:     "https://example.com",
 			Img:     "http://example.com/pic.jpg",
 			Cost: &MessageOrderCost{
-				Value:    29900,
+				Value:    "29900",
 				Currency: "rub",
 			},
 			Quantity: &MessageOrderQuantity{
-				Value: 1,
+				Value: "1",
 			},
 		},
 	}
@@ -753,7 +803,7 @@ func TestMgClient_MessageSendOrder(t *testing.T) {
 		Order: &MessageOrder{
 			Number: RandStringBytesMaskImprSrc(7),
 			Cost: &MessageOrderCost{
-				Value:    29900,
+				Value:    "29900",
 				Currency: MsgCurrencyRub,
 			},
 			Status: &MessageOrderStatus{
@@ -764,7 +814,7 @@ func TestMgClient_MessageSendOrder(t *testing.T) {
 				Name:    "Курьерская доставка",
 				Address: "г. Москва, Проспект Мира, 9",
 				Price: &MessageOrderCost{
-					Value:    1100,
+					Value:    "1100",
 					Currency: MsgCurrencyRub,
 				},
 			},
@@ -774,11 +824,11 @@ func TestMgClient_MessageSendOrder(t *testing.T) {
 					Url:  "https://example.com/product.html",
 					Img:  "https://example.com/picture.png",
 					Price: &MessageOrderCost{
-						Value:    29900,
+						Value:    "29900",
 						Currency: MsgCurrencyRub,
 					},
 					Quantity: &MessageOrderQuantity{
-						Value: 1,
+						Value: "1",
 					},
 				},
 			},
@@ -1121,6 +1171,47 @@ func TestMgClient_SuccessChatsByCustomerId(t *testing.T) {
 	assert.Equal(t, "Фамилия", resp[0].Customer.LastName)
 }
 
+func TestMgClient_MessageSendContext(t *testing.T) {
+	c := client()
+
+	message := MessageSendRequest{
+		Type:    MsgTypeText,
+		Scope:   MessageScopePublic,
+		Content: "test",
+		ChatID:  1,
+	}
+
+	defer gock.Off()
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/messages").
+		JSON(message).
+		Reply(200).
+		BodyString(`{"message_id": 1, "time": "2018-01-01T00:00:00+03:00"}`)
+
+	data, status, err := c.MessageSendContext(context.Background(), message)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, uint64(1), data.MessageID)
+}
+
+func TestMgClient_ContextCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, status, err := c.BotsContext(ctx, BotsRequest{})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 0, status)
+}
+
 func getJSONResponseChats() string {
 	return `[
 		{
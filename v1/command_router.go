@@ -0,0 +1,274 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ErrNotCommand is returned by CommandRouter.HandleMessage for messages which are not bot commands.
+var ErrNotCommand = errors.New("message is not a command")
+
+// CommandCall is a parsed bot command.
+type CommandCall struct {
+	// Name is the command name without leading slash, e.g. "start".
+	Name string
+	// Args contains space separated arguments. Quoted arguments ("foo bar" or 'foo bar') are kept together.
+	Args []string
+	// RawArgs is the command content after the name as is.
+	RawArgs string
+	// Message is the original message. It is nil if the command was parsed from a string.
+	Message *Message
+}
+
+// Arg returns i-th argument or an empty string if there is no such argument.
+func (c *CommandCall) Arg(i int) string {
+	if i < 0 || i >= len(c.Args) {
+		return ""
+	}
+
+	return c.Args[i]
+}
+
+// IntArg parses i-th argument as integer.
+func (c *CommandCall) IntArg(i int) (int, error) {
+	return strconv.Atoi(c.Arg(i))
+}
+
+// CommandHandler handles a bot command.
+type CommandHandler func(ctx context.Context, call *CommandCall) error
+
+type commandRoute struct {
+	description string
+	handler     CommandHandler
+}
+
+// CommandRouter dispatches bot commands received as MsgTypeCommand messages to registered handlers.
+//
+// Example:
+//
+//	router := v1.NewCommandRouter()
+//	router.Handle("order", "Show order status", func(ctx context.Context, call *v1.CommandCall) error {
+//		_, _, err := client.MessageSendContext(ctx, v1.MessageSendRequest{
+//			Type:    v1.MsgTypeText,
+//			Scope:   v1.MessageScopePublic,
+//			ChatID:  call.Message.ChatID,
+//			Content: "Order " + call.Arg(0) + " is on its way",
+//		})
+//		return err
+//	})
+//
+//	if err := router.Sync(ctx, client); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	dispatcher := v1.NewWsDispatcher()
+//	dispatcher.OnMessageNew(router.OnMessageNew)
+type CommandRouter struct {
+	mu       sync.RWMutex
+	routes   map[string]commandRoute
+	fallback CommandHandler
+}
+
+// NewCommandRouter returns empty CommandRouter.
+func NewCommandRouter() *CommandRouter {
+	return &CommandRouter{routes: map[string]commandRoute{}}
+}
+
+// Handle registers handler for the command. Description is used by Sync.
+func (r *CommandRouter) Handle(name, description string, handler CommandHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routes[strings.TrimPrefix(name, "/")] = commandRoute{description: description, handler: handler}
+}
+
+// OnUnknown sets handler for commands without registered handler.
+func (r *CommandRouter) OnUnknown(handler CommandHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fallback = handler
+}
+
+// Commands returns registered commands sorted by name.
+func (r *CommandRouter) Commands() []CommandEditRequest {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	commands := make([]CommandEditRequest, 0, len(r.routes))
+	for name, route := range r.routes {
+		commands = append(commands, CommandEditRequest{Name: name, Description: route.description})
+	}
+
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Name < commands[j].Name
+	})
+
+	return commands
+}
+
+// OnMessageNew handles message_new event data. It can be registered in WsDispatcher as is.
+// Messages which are not commands are ignored.
+func (r *CommandRouter) OnMessageNew(ctx context.Context, data *WsEventMessageNewData) error {
+	if data == nil || data.Message == nil {
+		return nil
+	}
+
+	err := r.HandleMessage(ctx, data.Message)
+	if errors.Is(err, ErrNotCommand) {
+		return nil
+	}
+
+	return err
+}
+
+// HandleMessage parses the message and calls the handler of the command.
+// It returns ErrNotCommand if the message is not a command.
+func (r *CommandRouter) HandleMessage(ctx context.Context, message *Message) error {
+	if message.Type != MsgTypeCommand || message.TextMessage == nil {
+		return ErrNotCommand
+	}
+
+	call, ok := ParseCommand(message.Content)
+	if !ok {
+		return ErrNotCommand
+	}
+
+	call.Message = message
+
+	return r.Dispatch(ctx, call)
+}
+
+// Dispatch calls the handler of the parsed command or the unknown command handler.
+func (r *CommandRouter) Dispatch(ctx context.Context, call *CommandCall) error {
+	r.mu.RLock()
+	route, ok := r.routes[call.Name]
+	fallback := r.fallback
+	r.mu.RUnlock()
+
+	if ok {
+		return route.handler(ctx, call)
+	}
+
+	if fallback != nil {
+		return fallback(ctx, call)
+	}
+
+	return nil
+}
+
+// Sync registers the router commands in MG via CommandEdit and deletes other commands of the bot.
+// Commands with unchanged description are not updated.
+func (r *CommandRouter) Sync(ctx context.Context, client *MgClient) error {
+	existing := map[string]string{}
+
+	it := client.IterateCommands(ctx, CommandsRequest{})
+	for it.Next() {
+		existing[it.Item().Name] = it.Item().Description
+	}
+
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	for _, command := range r.Commands() {
+		if description, ok := existing[command.Name]; ok && description == command.Description {
+			delete(existing, command.Name)
+			continue
+		}
+
+		delete(existing, command.Name)
+		if _, _, err := client.CommandEditContext(ctx, command); err != nil {
+			return err
+		}
+	}
+
+	stale := make([]string, 0, len(existing))
+	for name := range existing {
+		stale = append(stale, name)
+	}
+
+	sort.Strings(stale)
+	for _, name := range stale {
+		if _, _, err := client.CommandDeleteContext(ctx, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParseCommand parses "/name arg1 arg2" string. The bot name suffix ("/start@my_bot") is dropped.
+// It returns false if the string is not a command.
+func ParseCommand(content string) (*CommandCall, bool) {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "/") {
+		return nil, false
+	}
+
+	content = content[1:]
+	end := strings.IndexFunc(content, unicode.IsSpace)
+	if end < 0 {
+		end = len(content)
+	}
+
+	name := content[:end]
+	if at := strings.IndexByte(name, '@'); at >= 0 {
+		name = name[:at]
+	}
+
+	if name == "" {
+		return nil, false
+	}
+
+	rawArgs := strings.TrimSpace(content[end:])
+
+	return &CommandCall{
+		Name:    name,
+		Args:    splitCommandArgs(rawArgs),
+		RawArgs: rawArgs,
+	}, true
+}
+
+// splitCommandArgs splits arguments by spaces keeping quoted parts together. A quote opens only at the start
+// of an argument, quotes inside words (e.g. apostrophes in "don't") are kept as is.
+func splitCommandArgs(s string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		quote   rune
+		inArg   bool
+	)
+
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case !inArg && (r == '"' || r == '\''):
+			quote = r
+			inArg = true
+		case unicode.IsSpace(r):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args
+}
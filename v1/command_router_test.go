@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func commandMessage(content string) *Message {
+	return &Message{
+		ID:          1,
+		Type:        MsgTypeCommand,
+		ChatID:      10,
+		TextMessage: &TextMessage{Content: content},
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	call, ok := ParseCommand(`  /order@my_bot 123 "new address" 'a b' c  `)
+	require.True(t, ok)
+	assert.Equal(t, "order", call.Name)
+	assert.Equal(t, []string{"123", "new address", "a b", "c"}, call.Args)
+	assert.Equal(t, `123 "new address" 'a b' c`, call.RawArgs)
+	assert.Equal(t, "", call.Arg(10))
+
+	id, err := call.IntArg(0)
+	require.NoError(t, err)
+	assert.Equal(t, 123, id)
+
+	call, ok = ParseCommand("/help")
+	require.True(t, ok)
+	assert.Equal(t, "help", call.Name)
+	assert.Empty(t, call.Args)
+
+	call, ok = ParseCommand(`/say don't worry, it's "fine" 'ok'`)
+	require.True(t, ok)
+	assert.Equal(t, []string{"don't", "worry,", "it's", "fine", "ok"}, call.Args)
+
+	call, ok = ParseCommand(`/say "it's fine" rock'n'roll`)
+	require.True(t, ok)
+	assert.Equal(t, []string{"it's fine", "rock'n'roll"}, call.Args)
+
+	_, ok = ParseCommand("hello /help")
+	assert.False(t, ok)
+	_, ok = ParseCommand("/ help")
+	assert.False(t, ok)
+}
+
+func TestCommandRouter_HandleMessage(t *testing.T) {
+	router := NewCommandRouter()
+
+	var called []string
+	router.Handle("/start", "Start", func(ctx context.Context, call *CommandCall) error {
+		called = append(called, "start:"+call.RawArgs)
+		assert.Equal(t, uint64(10), call.Message.ChatID)
+		return nil
+	})
+	router.Handle("fail", "Fail", func(ctx context.Context, call *CommandCall) error {
+		return errors.New("handler error")
+	})
+
+	require.NoError(t, router.HandleMessage(context.Background(), commandMessage("/start now")))
+	require.NoError(t, router.HandleMessage(context.Background(), commandMessage("/unknown")))
+	assert.EqualError(t, router.HandleMessage(context.Background(), commandMessage("/fail")), "handler error")
+
+	text := commandMessage("/start")
+	text.Type = MsgTypeText
+	assert.True(t, errors.Is(router.HandleMessage(context.Background(), text), ErrNotCommand))
+	assert.NoError(t, router.OnMessageNew(context.Background(), &WsEventMessageNewData{Message: text}))
+
+	router.OnUnknown(func(ctx context.Context, call *CommandCall) error {
+		called = append(called, "unknown:"+call.Name)
+		return nil
+	})
+	require.NoError(t, router.OnMessageNew(context.Background(), &WsEventMessageNewData{
+		Message: commandMessage("/unknown"),
+	}))
+
+	assert.Equal(t, []string{"start:now", "unknown:unknown"}, called)
+}
+
+func TestCommandRouter_Sync(t *testing.T) {
+	var edited, deleted []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet:
+			if r.URL.Query().Get("since_id") != "" {
+				_, _ = w.Write([]byte(`[]`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`[
+				{"id": 1, "name": "start", "description": "Start"},
+				{"id": 2, "name": "help", "description": "Old help"},
+				{"id": 3, "name": "stale", "description": "Stale"}
+			]`))
+		case r.Method == http.MethodPut:
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			var req CommandEditRequest
+			require.NoError(t, json.Unmarshal(body, &req))
+			assert.Equal(t, "/api/bot/v1/my/commands/"+req.Name, r.URL.Path)
+			edited = append(edited, req.Name+":"+req.Description)
+
+			_, _ = w.Write(body)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	handler := func(ctx context.Context, call *CommandCall) error { return nil }
+
+	router := NewCommandRouter()
+	router.Handle("start", "Start", handler)
+	router.Handle("help", "Help", handler)
+	router.Handle("order", "Order status", handler)
+
+	require.NoError(t, router.Sync(context.Background(), New(srv.URL, mgToken)))
+	assert.Equal(t, []string{"help:Help", "order:Order status"}, edited)
+	assert.Equal(t, []string{"/api/bot/v1/my/commands/stale"}, deleted)
+}
@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxDecimalExponent limits the exponent of parsed numbers to keep their decimal representation short.
+const maxDecimalExponent = 64
+
+// Decimal is an exact decimal number used for money amounts and quantities in order and product messages.
+// It holds the number in decimal notation (e.g. "19999.99") and is encoded to JSON as a number without rounding.
+// The empty Decimal is zero.
+//
+// Example:
+//
+//	price := v1.NewDecimal(19999.99)
+//	price = v1.NewDecimalFromMinorUnits(1999999, 2)
+//	price, err := v1.ParseDecimal("19999.99")
+//	price = "19999.99"
+type Decimal string
+
+// NewDecimal returns the shortest decimal representation of the float, e.g. NewDecimal(19999.99) is "19999.99".
+// NaN and infinite values result in an invalid Decimal which fails to encode.
+func NewDecimal(value float64) Decimal {
+	return Decimal(strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// NewDecimalFromMinorUnits returns the decimal for the amount in minor units (e.g. cents) and the number of
+// minor unit digits: NewDecimalFromMinorUnits(1999999, 2) is "19999.99".
+func NewDecimalFromMinorUnits(units int64, scale int) Decimal {
+	digits := strconv.FormatUint(absInt64(units), 10)
+
+	return formatDecimal(units < 0, digits, scale)
+}
+
+// ParseDecimal parses the number in decimal or exponent notation and returns it in decimal notation.
+func ParseDecimal(value string) (Decimal, error) {
+	neg, digits, scale, err := parseDecimal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return formatDecimal(neg, digits, scale), nil
+}
+
+// String returns the number in decimal notation, "0" for the empty Decimal.
+func (d Decimal) String() string {
+	if d == "" {
+		return "0"
+	}
+
+	return string(d)
+}
+
+// Float64 returns the nearest float value. It returns 0 if the Decimal is invalid.
+func (d Decimal) Float64() float64 {
+	value, _ := strconv.ParseFloat(d.String(), 64)
+	return value
+}
+
+// Sign returns -1, 0 or 1 depending on the sign of the number. It returns 0 if the Decimal is invalid.
+func (d Decimal) Sign() int {
+	neg, digits, _, err := parseDecimal(d.String())
+	switch {
+	case err != nil || strings.Trim(digits, "0") == "":
+		return 0
+	case neg:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// Equal reports whether the numbers are equal regardless of notation, e.g. "10.5" equals "10.50".
+func (d Decimal) Equal(other Decimal) bool {
+	a, errA := ParseDecimal(d.String())
+	b, errB := ParseDecimal(other.String())
+
+	return errA == nil && errB == nil && trimFraction(a) == trimFraction(b)
+}
+
+// MinorUnits returns the number multiplied by 10^scale, e.g. the amount in cents for scale 2.
+// It returns an error if the number has more fractional digits than scale or does not fit into int64.
+func (d Decimal) MinorUnits(scale int) (int64, error) {
+	neg, digits, digitsScale, err := parseDecimal(d.String())
+	if err != nil {
+		return 0, err
+	}
+
+	if digitsScale > scale {
+		cut := len(digits) - (digitsScale - scale)
+		if strings.Trim(digits[cut:], "0") != "" {
+			return 0, fmt.Errorf("decimal %s has more than %d fractional digits", d, scale)
+		}
+
+		digits = digits[:cut]
+	} else {
+		digits += strings.Repeat("0", scale-digitsScale)
+	}
+
+	if digits == "" {
+		digits = "0"
+	}
+
+	if neg {
+		digits = "-" + digits
+	}
+
+	units, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("decimal %s is out of range", d)
+	}
+
+	return units, nil
+}
+
+// MarshalJSON encodes the number as JSON number in decimal notation.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	value, err := ParseDecimal(d.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(value), nil
+}
+
+// UnmarshalJSON decodes JSON number, numeric string or null which results in the empty Decimal.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*d = ""
+		return nil
+	}
+
+	value := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseDecimal(value)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// parseDecimal splits the number into the sign, all significant digits and the number of fractional digits.
+func parseDecimal(value string) (neg bool, digits string, scale int, err error) {
+	invalid := fmt.Errorf("invalid decimal %q", value)
+
+	mantissa, exponent := value, 0
+	if i := strings.IndexAny(value, "eE"); i >= 0 {
+		mantissa = value[:i]
+		if exponent, err = strconv.Atoi(value[i+1:]); err != nil || exponent > maxDecimalExponent ||
+			exponent < -maxDecimalExponent {
+			return false, "", 0, invalid
+		}
+	}
+
+	if strings.HasPrefix(mantissa, "-") || strings.HasPrefix(mantissa, "+") {
+		neg = mantissa[0] == '-'
+		mantissa = mantissa[1:]
+	}
+
+	intPart, fracPart := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		intPart, fracPart = mantissa[:i], mantissa[i+1:]
+	}
+
+	digits = intPart + fracPart
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return false, "", 0, invalid
+	}
+
+	scale = len(fracPart) - exponent
+	if scale < 0 {
+		digits += strings.Repeat("0", -scale)
+		scale = 0
+	}
+
+	return neg, digits, scale, nil
+}
+
+// formatDecimal formats the digits with the given number of fractional digits.
+func formatDecimal(neg bool, digits string, scale int) Decimal {
+	if scale < 0 {
+		digits += strings.Repeat("0", -scale)
+		scale = 0
+	}
+
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+
+	intPart := strings.TrimLeft(digits[:len(digits)-scale], "0")
+	if intPart == "" {
+		intPart = "0"
+	}
+
+	result := intPart
+	if scale > 0 {
+		result += "." + digits[len(digits)-scale:]
+	}
+
+	if neg && strings.Trim(digits, "0") != "" {
+		result = "-" + result
+	}
+
+	return Decimal(result)
+}
+
+// trimFraction removes insignificant fractional zeros.
+func trimFraction(d Decimal) string {
+	value := string(d)
+	if strings.IndexByte(value, '.') < 0 {
+		return value
+	}
+
+	return strings.TrimSuffix(strings.TrimRight(value, "0"), ".")
+}
+
+func absInt64(value int64) uint64 {
+	if value < 0 {
+		return uint64(-(value + 1)) + 1
+	}
+
+	return uint64(value)
+}
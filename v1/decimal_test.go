@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDecimal(t *testing.T) {
+	assert.Equal(t, Decimal("19999.99"), NewDecimal(19999.99))
+	assert.Equal(t, Decimal("0.1"), NewDecimal(0.1))
+	assert.Equal(t, Decimal("100"), NewDecimal(100))
+	assert.Equal(t, Decimal("-2.5"), NewDecimal(-2.5))
+
+	_, err := NewDecimal(math.NaN()).MarshalJSON()
+	assert.Error(t, err)
+}
+
+func TestNewDecimalFromMinorUnits(t *testing.T) {
+	assert.Equal(t, Decimal("19999.99"), NewDecimalFromMinorUnits(1999999, 2))
+	assert.Equal(t, Decimal("0.05"), NewDecimalFromMinorUnits(5, 2))
+	assert.Equal(t, Decimal("-0.05"), NewDecimalFromMinorUnits(-5, 2))
+	assert.Equal(t, Decimal("10.00"), NewDecimalFromMinorUnits(1000, 2))
+	assert.Equal(t, Decimal("7"), NewDecimalFromMinorUnits(7, 0))
+	assert.Equal(t, Decimal("-9223372036854775.808"), NewDecimalFromMinorUnits(math.MinInt64, 3))
+}
+
+func TestParseDecimal(t *testing.T) {
+	cases := map[string]Decimal{
+		"19999.99": "19999.99",
+		"+1.50":    "1.50",
+		"007":      "7",
+		".5":       "0.5",
+		"1.":       "1",
+		"1e3":      "1000",
+		"1.25E-3":  "0.00125",
+		"-0":       "0",
+	}
+
+	for value, expected := range cases {
+		d, err := ParseDecimal(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, d, value)
+	}
+
+	for _, value := range []string{"", "-", "1,5", "1.2.3", "abc", "NaN", "1e", "1e1000"} {
+		_, err := ParseDecimal(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestDecimal_MinorUnits(t *testing.T) {
+	units, err := Decimal("19999.99").MinorUnits(2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1999999), units)
+
+	units, err = Decimal("-3.1").MinorUnits(2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-310), units)
+
+	units, err = Decimal("12.500").MinorUnits(2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1250), units)
+
+	units, err = Decimal("").MinorUnits(2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), units)
+
+	_, err = Decimal("0.125").MinorUnits(2)
+	assert.Error(t, err)
+
+	_, err = Decimal("1e20").MinorUnits(0)
+	assert.Error(t, err)
+}
+
+func TestDecimal_Compare(t *testing.T) {
+	assert.True(t, Decimal("10.5").Equal("10.50"))
+	assert.True(t, Decimal("").Equal("0.00"))
+	assert.False(t, Decimal("10.5").Equal("10.05"))
+	assert.False(t, Decimal("x").Equal("x"))
+
+	assert.Equal(t, 1, Decimal("0.01").Sign())
+	assert.Equal(t, -1, Decimal("-2").Sign())
+	assert.Equal(t, 0, Decimal("0.00").Sign())
+	assert.Equal(t, 0, Decimal("").Sign())
+
+	assert.Equal(t, 19999.99, Decimal("19999.99").Float64())
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	var cost MessageOrderCost
+	require.NoError(t, json.Unmarshal([]byte(`{"value": 19999.99, "currency": "rub"}`), &cost))
+	assert.Equal(t, Decimal("19999.99"), cost.Value)
+
+	data, err := json.Marshal(cost)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"value": 19999.99, "currency": "rub"}`, string(data))
+
+	var quantity MessageOrderQuantity
+	require.NoError(t, json.Unmarshal([]byte(`{"value": 12345678901234567.89, "unit": "kg"}`), &quantity))
+	data, err = json.Marshal(quantity)
+	require.NoError(t, err)
+	assert.Equal(t, `{"value":12345678901234567.89,"unit":"kg"}`, string(data))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"value": "1.5", "unit": "kg"}`), &quantity))
+	assert.Equal(t, Decimal("1.5"), quantity.Value)
+
+	data, err = json.Marshal(MessageOrderQuantity{Unit: "pcs"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"value":0,"unit":"pcs"}`, string(data))
+
+	data, err = json.Marshal(MessageOrderCost{Currency: "rub"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"currency":"rub"}`, string(data))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"value": true}`), &quantity))
+	assert.Error(t, json.Unmarshal([]byte(`{"value": "ten"}`), &quantity))
+}
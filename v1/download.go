@@ -0,0 +1,183 @@
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrFileCorrupted is returned when the downloaded file size or hash does not match the expected one.
+var ErrFileCorrupted = errors.New("downloaded file is corrupted")
+
+// ErrHashUnsupported is returned when the hash passed with DownloadHash is not a hex encoded SHA-256 sum,
+// so the file can't be verified. The file is not downloaded in this case.
+var ErrHashUnsupported = errors.New("file hash can't be verified")
+
+// DownloadOption configures file downloads.
+type DownloadOption func(*downloadConfig)
+
+type downloadConfig struct {
+	hash string
+}
+
+// DownloadHash sets the expected file hash (see UploadFileResponse.Hash). The hash must be a hex encoded SHA-256 sum
+// of the file contents, otherwise ErrHashUnsupported is returned. The comparison is case-insensitive.
+func DownloadHash(hash string) DownloadOption {
+	return func(cfg *downloadConfig) {
+		cfg.hash = strings.ToLower(hash)
+	}
+}
+
+// DownloadFile writes the file contents to the writer. It returns an error matching ErrFileCorrupted
+// if the downloaded size does not match FullFileResponse.Size or the hash does not match DownloadHash,
+// and ErrHashUnsupported if the hash can't be checked.
+// The writer may contain partially written data in case of an error.
+//
+// Example:
+//
+//	f, err := os.Create("/tmp/attachment")
+//	if err != nil {
+//		return err
+//	}
+//	defer f.Close()
+//
+//	info, err := client.DownloadFile(ctx, item.ID, f)
+func (c *MgClient) DownloadFile(
+	ctx context.Context, fileID string, w io.Writer, opts ...DownloadOption,
+) (FullFileResponse, error) {
+	body, info, err := c.OpenFile(ctx, fileID, opts...)
+	if err != nil {
+		return info, err
+	}
+	defer body.Close()
+
+	_, err = io.Copy(w, body)
+
+	return info, err
+}
+
+// OpenFile resolves the file URL and returns the file contents stream, which must be closed by the caller.
+// The size and hash are verified while reading: the last Read returns an error matching ErrFileCorrupted
+// instead of io.EOF if they don't match.
+//
+// The file URL is requested without the bot token, so it is not sent to a third-party storage.
+func (c *MgClient) OpenFile(
+	ctx context.Context, fileID string, opts ...DownloadOption,
+) (io.ReadCloser, FullFileResponse, error) {
+	var cfg downloadConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.hash != "" && !isSHA256(cfg.hash) {
+		return nil, FullFileResponse{}, fmt.Errorf("%w: file %s hash %q is not a SHA-256 sum",
+			ErrHashUnsupported, fileID, cfg.hash)
+	}
+
+	info, _, err := c.GetFileContext(ctx, fileID)
+	if err != nil {
+		return nil, info, err
+	}
+
+	if info.Url == "" {
+		return nil, info, fmt.Errorf("file %s: empty url", fileID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, info.Url, nil)
+	if err != nil {
+		return nil, info, err
+	}
+
+	if c.Debug {
+		c.writeLog("MG BOT API File download: %s %s", fileID, info.Url)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, info, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := buildRawResponse(resp)
+		return nil, info, newAPIError(http.MethodGet, req.URL.Path, resp.StatusCode, data)
+	}
+
+	size := int64(info.Size)
+	if size > 0 && resp.ContentLength >= 0 && resp.ContentLength != size {
+		resp.Body.Close()
+		return nil, info, sizeMismatch(fileID, size, resp.ContentLength)
+	}
+
+	body := &downloadReader{body: resp.Body, fileID: fileID, size: size, hash: cfg.hash}
+	if cfg.hash != "" {
+		body.hasher = sha256.New()
+	}
+
+	return body, info, nil
+}
+
+// downloadReader verifies the file size and hash when the body is read to the end.
+type downloadReader struct {
+	body   io.ReadCloser
+	fileID string
+	size   int64
+	read   int64
+	hash   string
+	hasher hash.Hash
+}
+
+func (r *downloadReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	r.read += int64(n)
+
+	if r.hasher != nil {
+		_, _ = r.hasher.Write(p[:n])
+	}
+
+	if errors.Is(err, io.EOF) {
+		if e := r.verify(); e != nil {
+			return n, e
+		}
+	}
+
+	return n, err
+}
+
+func (r *downloadReader) verify() error {
+	if r.size > 0 && r.read != r.size {
+		return sizeMismatch(r.fileID, r.size, r.read)
+	}
+
+	if r.hasher != nil {
+		if sum := hex.EncodeToString(r.hasher.Sum(nil)); sum != r.hash {
+			return fmt.Errorf("%w: file %s hash is %s, got %s", ErrFileCorrupted, r.fileID, r.hash, sum)
+		}
+	}
+
+	return nil
+}
+
+func (r *downloadReader) Close() error {
+	return r.body.Close()
+}
+
+// isSHA256 returns true if the hash looks like a hex encoded SHA-256 sum.
+func isSHA256(hash string) bool {
+	if len(hash) != hex.EncodedLen(sha256.Size) {
+		return false
+	}
+
+	_, err := hex.DecodeString(hash)
+
+	return err == nil
+}
+
+func sizeMismatch(fileID string, expected, actual int64) error {
+	return fmt.Errorf("%w: file %s size is %d, got %d bytes", ErrFileCorrupted, fileID, expected, actual)
+}
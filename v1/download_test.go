@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+const storageURL = "https://storage.example.com"
+
+var downloadContent = []byte("file contents")
+
+// mockFile mocks the file info with the given size and the file contents in the storage.
+// The storage request is matched only if it is sent without the bot token.
+func mockFile(fileID string, size int) {
+	gock.New(mgURL).
+		Get("/api/bot/v1/files/" + fileID).
+		Reply(200).
+		JSON(FullFileResponse{ID: fileID, Type: "file", Size: size, Url: storageURL + "/files/" + fileID})
+
+	gock.New(storageURL).
+		Get("/files/"+fileID).
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			return req.Header.Get("X-Bot-Token") == "", nil
+		}).
+		Reply(200).
+		Body(bytes.NewReader(downloadContent))
+}
+
+func TestMgClient_DownloadFile(t *testing.T) {
+	defer gock.Off()
+
+	mockFile("file", len(downloadContent))
+	sum := sha256.Sum256(downloadContent)
+
+	var buf bytes.Buffer
+	info, err := client().DownloadFile(context.Background(), "file", &buf,
+		DownloadHash(strings.ToUpper(hex.EncodeToString(sum[:]))))
+	require.NoError(t, err)
+	assert.Equal(t, "file", info.ID)
+	assert.Equal(t, downloadContent, buf.Bytes())
+	assert.True(t, gock.IsDone(), "bot token must not be sent to the storage")
+}
+
+func TestMgClient_DownloadFileHashMismatch(t *testing.T) {
+	defer gock.Off()
+
+	mockFile("file", len(downloadContent))
+
+	var buf bytes.Buffer
+	_, err := client().DownloadFile(context.Background(), "file", &buf, DownloadHash(strings.Repeat("ab", 32)))
+	assert.True(t, errors.Is(err, ErrFileCorrupted))
+}
+
+func TestMgClient_DownloadFileHashUnsupported(t *testing.T) {
+	defer gock.Off()
+
+	var requests requestRecorder
+	for _, hash := range []string{"deadbeef", strings.Repeat("zz", 32)} {
+		var buf bytes.Buffer
+		_, err := client(requests.option()).DownloadFile(context.Background(), "file", &buf, DownloadHash(hash))
+		assert.True(t, errors.Is(err, ErrHashUnsupported), hash)
+		assert.False(t, errors.Is(err, ErrFileCorrupted), hash)
+		assert.Empty(t, buf.Bytes())
+	}
+
+	assert.Empty(t, requests.all(), "file must not be requested")
+}
+
+func TestMgClient_OpenFileSizeMismatch(t *testing.T) {
+	defer gock.Off()
+
+	// The file size reported by the API doesn't match the stored contents.
+	mockFile("file", 100)
+
+	body, _, err := client().OpenFile(context.Background(), "file")
+	assert.Nil(t, body)
+	assert.True(t, errors.Is(err, ErrFileCorrupted))
+}
+
+func TestMgClient_OpenFile(t *testing.T) {
+	defer gock.Off()
+
+	mockFile("file", len(downloadContent))
+
+	body, info, err := client().OpenFile(context.Background(), "file")
+	require.NoError(t, err)
+	defer body.Close()
+
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, downloadContent, data)
+	assert.Equal(t, len(downloadContent), info.Size)
+}
+
+func TestMgClient_OpenFileNotFound(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mgURL).
+		Get("/api/bot/v1/files/missing").
+		Reply(404).
+		BodyString(`{"errors": ["File not found"]}`)
+
+	_, _, err := client().OpenFile(context.Background(), "missing")
+	assert.True(t, IsNotFound(err))
+}
@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrNotFound matches APIError with 404 Not Found status.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited matches APIError with 429 Too Many Requests status.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUnauthorized matches APIError with 401 Unauthorized or 403 Forbidden status.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrValidation matches APIError with 400 Bad Request or 422 Unprocessable Entity status.
+	ErrValidation = errors.New("validation error")
+)
+
+// APIError is returned by MgClient methods when MG Bot API responds with an error status.
+//
+// Example:
+//
+//	_, _, err := client.DialogAssign(DialogAssignRequest{DialogID: 1, UserID: 6})
+//
+//	var apiErr *v1.APIError
+//	if errors.As(err, &apiErr) {
+//		fmt.Printf("%d %s %v\n", apiErr.StatusCode, apiErr.Endpoint, apiErr.Errors)
+//	}
+//
+//	if errors.Is(err, v1.ErrNotFound) {
+//		fmt.Println("dialog not found")
+//	}
+type APIError struct {
+	// StatusCode is the HTTP status of the response.
+	StatusCode int
+	// Method is the HTTP method of the request.
+	Method string
+	// Endpoint is the request path relative to the API prefix, without query.
+	Endpoint string
+	// Errors contains all error messages from the response body.
+	Errors []string
+	// Body is the raw response body.
+	Body []byte
+}
+
+// Error returns all error messages joined with "; ".
+func (e *APIError) Error() string {
+	if len(e.Errors) > 0 {
+		return strings.Join(e.Errors, "; ")
+	}
+
+	if e.StatusCode == 0 {
+		return "http request error"
+	}
+
+	return fmt.Sprintf("http request error. Status code: %d", e.StatusCode)
+}
+
+// Is reports whether the error matches one of the sentinel errors: ErrNotFound, ErrRateLimited,
+// ErrUnauthorized or ErrValidation.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	default:
+		return false
+	}
+}
+
+// IsNotFound returns true if err is APIError with 404 Not Found status.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsRateLimited returns true if err is APIError with 429 Too Many Requests status.
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsUnauthorized returns true if err is APIError with 401 Unauthorized or 403 Forbidden status.
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsValidation returns true if err is APIError with 400 Bad Request or 422 Unprocessable Entity status.
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// newAPIError builds APIError from the response. It never fails: if the body cannot be parsed,
+// the error contains only the status and the raw body.
+func newAPIError(method, endpoint string, status int, body []byte) *APIError {
+	if i := strings.IndexByte(endpoint, '?'); i >= 0 {
+		endpoint = endpoint[:i]
+	}
+
+	return &APIError{
+		StatusCode: status,
+		Method:     method,
+		Endpoint:   endpoint,
+		Errors:     parseErrorMessages(body),
+		Body:       body,
+	}
+}
+
+func parseErrorMessages(body []byte) []string {
+	var data struct {
+		Errors []interface{} `json:"errors"`
+	}
+
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil
+	}
+
+	messages := make([]string, 0, len(data.Errors))
+	for _, item := range data.Errors {
+		switch v := item.(type) {
+		case string:
+			messages = append(messages, v)
+		case nil:
+		default:
+			if encoded, err := json.Marshal(v); err == nil {
+				messages = append(messages, string(encoded))
+			}
+		}
+	}
+
+	return messages
+}
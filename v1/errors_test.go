@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestMgClient_APIError(t *testing.T) {
+	c := client()
+	defer gock.Off()
+
+	gock.New(mgURL).
+		Patch("/api/bot/v1/dialogs/444/unassign").
+		Reply(404).
+		BodyString(`{"errors": ["dialog #444 not found", "second error"]}`)
+
+	_, status, err := c.DialogUnassign(444)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, http.MethodPatch, apiErr.Method)
+	assert.Equal(t, "/dialogs/444/unassign", apiErr.Endpoint)
+	assert.Equal(t, []string{"dialog #444 not found", "second error"}, apiErr.Errors)
+	assert.Equal(t, "dialog #444 not found; second error", err.Error())
+
+	assert.True(t, IsNotFound(err))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.False(t, IsValidation(err))
+	assert.False(t, IsRateLimited(err))
+	assert.False(t, IsUnauthorized(err))
+}
+
+func TestMgClient_APIErrorEndpointWithoutQuery(t *testing.T) {
+	c := client()
+	defer gock.Off()
+
+	gock.New(mgURL).
+		Get("/api/bot/v1/bots").
+		Reply(401).
+		BodyString(`{"errors": ["wrong token"]}`)
+
+	_, _, err := c.Bots(BotsRequest{Active: 1})
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "/bots", apiErr.Endpoint)
+	assert.True(t, IsUnauthorized(err))
+}
+
+func TestMgClient_APIErrorServerError(t *testing.T) {
+	c := client()
+	defer gock.Off()
+
+	gock.New(mgURL).
+		Get("/api/bot/v1/my/commands").
+		Reply(503).
+		BodyString(`<html>Service Unavailable</html>`)
+
+	_, status, err := c.Commands(CommandsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "/my/commands", apiErr.Endpoint)
+	assert.Empty(t, apiErr.Errors)
+	assert.Equal(t, "<html>Service Unavailable</html>", string(apiErr.Body))
+	assert.Equal(t, "http request error. Status code: 503", err.Error())
+}
+
+func TestMgClient_ErrorUnexpectedBody(t *testing.T) {
+	c := client()
+
+	for _, body := range []string{``, `not json`, `{}`, `{"errors": "string"}`, `{"errors": [1, null, {"code": "x"}]}`} {
+		err := c.Error([]byte(body))
+		require.Error(t, err)
+	}
+
+	err := c.Error([]byte(`{"errors": [{"code": "x"}]}`))
+	assert.Equal(t, `{"code":"x"}`, err.Error())
+}
+
+func TestAPIError_Is(t *testing.T) {
+	assert.True(t, IsRateLimited(newAPIError(http.MethodGet, "/bots", http.StatusTooManyRequests, nil)))
+	assert.True(t, IsValidation(newAPIError(http.MethodPost, "/messages", http.StatusBadRequest, nil)))
+	assert.True(t, IsValidation(newAPIError(http.MethodPost, "/messages", http.StatusUnprocessableEntity, nil)))
+	assert.True(t, IsUnauthorized(newAPIError(http.MethodGet, "/bots", http.StatusForbidden, nil)))
+	assert.False(t, IsNotFound(errors.New("not found")))
+	assert.False(t, IsNotFound(nil))
+}
@@ -0,0 +1,444 @@
+package v1
+
+import (
+	"context"
+)
+
+// IterateOption configures list iterators.
+type IterateOption func(*iterateConfig)
+
+type iterateConfig struct {
+	maxItems int
+}
+
+// IterateMaxItems stops iteration after n items. Zero means no limit.
+func IterateMaxItems(n int) IterateOption {
+	return func(cfg *iterateConfig) {
+		cfg.maxItems = n
+	}
+}
+
+// pageCursor walks through a list endpoint using since_id cursor. It implements Next and Err methods
+// of all iterators; the typed iterators keep the current page and implement Item.
+type pageCursor struct {
+	ctx      context.Context
+	load     func(ctx context.Context, sinceID uint64) (size int, lastID uint64, err error)
+	sinceID  uint64
+	limit    int
+	maxItems int
+	yielded  int
+	index    int
+	size     int
+	done     bool
+	err      error
+}
+
+func newPageCursor(
+	ctx context.Context, sinceID uint64, limit int, opts []IterateOption,
+	load func(ctx context.Context, sinceID uint64) (int, uint64, error),
+) pageCursor {
+	var cfg iterateConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return pageCursor{
+		ctx:      ctx,
+		load:     load,
+		sinceID:  sinceID,
+		limit:    limit,
+		maxItems: cfg.maxItems,
+		index:    -1,
+	}
+}
+
+// Next advances the iterator to the next item, loading the next page if needed.
+// It returns false when there are no more items or an error occurred; check Err in that case.
+func (c *pageCursor) Next() bool {
+	if c.err != nil || (c.maxItems > 0 && c.yielded >= c.maxItems) {
+		return false
+	}
+
+	c.index++
+	if c.index >= c.size {
+		if c.done || !c.fetch() {
+			return false
+		}
+	}
+
+	c.yielded++
+
+	return true
+}
+
+// Err returns the first error occurred during iteration.
+func (c *pageCursor) Err() error {
+	return c.err
+}
+
+func (c *pageCursor) fetch() bool {
+	size, lastID, err := c.load(c.ctx, c.sinceID)
+	if err != nil {
+		c.err = err
+		return false
+	}
+
+	c.index = 0
+	c.size = size
+
+	if size == 0 || (c.limit > 0 && size < c.limit) || lastID <= c.sinceID {
+		c.done = true
+	}
+
+	if lastID > c.sinceID {
+		c.sinceID = lastID
+	}
+
+	return size > 0
+}
+
+// BotsIterator iterates over Bots results. Use Next, Item and Err methods.
+type BotsIterator struct {
+	pageCursor
+	items []BotsResponseItem
+}
+
+// Item returns the current item.
+func (it *BotsIterator) Item() BotsResponseItem {
+	return it.items[it.index]
+}
+
+// IterateBots returns iterator over all bots matching the request. Request Limit is used as page size.
+//
+// Example:
+//
+//	it := client.IterateBots(ctx, v1.BotsRequest{Active: 1})
+//	for it.Next() {
+//		fmt.Printf("%v\n", it.Item().Name)
+//	}
+//
+//	if err := it.Err(); err != nil {
+//		fmt.Printf("%v", err)
+//	}
+func (c *MgClient) IterateBots(ctx context.Context, request BotsRequest, opts ...IterateOption) *BotsIterator {
+	it := &BotsIterator{}
+	it.pageCursor = newPageCursor(ctx, request.SinceID, request.Limit, opts,
+		func(ctx context.Context, sinceID uint64) (int, uint64, error) {
+			request.SinceID = sinceID
+
+			items, _, err := c.BotsContext(ctx, request)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			it.items = items
+			var lastID uint64
+			for _, item := range items {
+				lastID = maxID(lastID, item.ID)
+			}
+
+			return len(items), lastID, nil
+		})
+
+	return it
+}
+
+// ChannelsIterator iterates over Channels results. Use Next, Item and Err methods.
+type ChannelsIterator struct {
+	pageCursor
+	items []ChannelResponseItem
+}
+
+// Item returns the current item.
+func (it *ChannelsIterator) Item() ChannelResponseItem {
+	return it.items[it.index]
+}
+
+// IterateChannels returns iterator over all channels matching the request. Request Limit is used as page size.
+func (c *MgClient) IterateChannels(
+	ctx context.Context, request ChannelsRequest, opts ...IterateOption,
+) *ChannelsIterator {
+	it := &ChannelsIterator{}
+	it.pageCursor = newPageCursor(ctx, request.SinceID, request.Limit, opts,
+		func(ctx context.Context, sinceID uint64) (int, uint64, error) {
+			request.SinceID = sinceID
+
+			items, _, err := c.ChannelsContext(ctx, request)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			it.items = items
+			var lastID uint64
+			for _, item := range items {
+				lastID = maxID(lastID, item.ID)
+			}
+
+			return len(items), lastID, nil
+		})
+
+	return it
+}
+
+// UsersIterator iterates over Users results. Use Next, Item and Err methods.
+type UsersIterator struct {
+	pageCursor
+	items []UsersResponseItem
+}
+
+// Item returns the current item.
+func (it *UsersIterator) Item() UsersResponseItem {
+	return it.items[it.index]
+}
+
+// IterateUsers returns iterator over all users matching the request. Request Limit is used as page size.
+func (c *MgClient) IterateUsers(ctx context.Context, request UsersRequest, opts ...IterateOption) *UsersIterator {
+	it := &UsersIterator{}
+	it.pageCursor = newPageCursor(ctx, request.SinceID, request.Limit, opts,
+		func(ctx context.Context, sinceID uint64) (int, uint64, error) {
+			request.SinceID = sinceID
+
+			items, _, err := c.UsersContext(ctx, request)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			it.items = items
+			var lastID uint64
+			for _, item := range items {
+				lastID = maxID(lastID, item.ID)
+			}
+
+			return len(items), lastID, nil
+		})
+
+	return it
+}
+
+// CustomersIterator iterates over Customers results. Use Next, Item and Err methods.
+type CustomersIterator struct {
+	pageCursor
+	items []CustomersResponseItem
+}
+
+// Item returns the current item.
+func (it *CustomersIterator) Item() CustomersResponseItem {
+	return it.items[it.index]
+}
+
+// IterateCustomers returns iterator over all customers matching the request. Request Limit is used as page size.
+func (c *MgClient) IterateCustomers(
+	ctx context.Context, request CustomersRequest, opts ...IterateOption,
+) *CustomersIterator {
+	it := &CustomersIterator{}
+	it.pageCursor = newPageCursor(ctx, request.SinceID, request.Limit, opts,
+		func(ctx context.Context, sinceID uint64) (int, uint64, error) {
+			request.SinceID = sinceID
+
+			items, _, err := c.CustomersContext(ctx, request)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			it.items = items
+			var lastID uint64
+			for _, item := range items {
+				lastID = maxID(lastID, item.ID)
+			}
+
+			return len(items), lastID, nil
+		})
+
+	return it
+}
+
+// ChatsIterator iterates over Chats results. Use Next, Item and Err methods.
+type ChatsIterator struct {
+	pageCursor
+	items []ChatResponseItem
+}
+
+// Item returns the current item.
+func (it *ChatsIterator) Item() ChatResponseItem {
+	return it.items[it.index]
+}
+
+// IterateChats returns iterator over all chats matching the request. Request Limit is used as page size.
+func (c *MgClient) IterateChats(ctx context.Context, request ChatsRequest, opts ...IterateOption) *ChatsIterator {
+	it := &ChatsIterator{}
+	it.pageCursor = newPageCursor(ctx, request.SinceID, request.Limit, opts,
+		func(ctx context.Context, sinceID uint64) (int, uint64, error) {
+			request.SinceID = sinceID
+
+			items, _, err := c.ChatsContext(ctx, request)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			it.items = items
+			var lastID uint64
+			for _, item := range items {
+				lastID = maxID(lastID, item.ID)
+			}
+
+			return len(items), lastID, nil
+		})
+
+	return it
+}
+
+// MembersIterator iterates over Members results. Use Next, Item and Err methods.
+type MembersIterator struct {
+	pageCursor
+	items []MemberResponseItem
+}
+
+// Item returns the current item.
+func (it *MembersIterator) Item() MemberResponseItem {
+	return it.items[it.index]
+}
+
+// IterateMembers returns iterator over all chat members matching the request. Request Limit is used as page size.
+func (c *MgClient) IterateMembers(
+	ctx context.Context, request MembersRequest, opts ...IterateOption,
+) *MembersIterator {
+	it := &MembersIterator{}
+	it.pageCursor = newPageCursor(ctx, request.SinceID, request.Limit, opts,
+		func(ctx context.Context, sinceID uint64) (int, uint64, error) {
+			request.SinceID = sinceID
+
+			items, _, err := c.MembersContext(ctx, request)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			it.items = items
+			var lastID uint64
+			for _, item := range items {
+				lastID = maxID(lastID, item.ID)
+			}
+
+			return len(items), lastID, nil
+		})
+
+	return it
+}
+
+// DialogsIterator iterates over Dialogs results. Use Next, Item and Err methods.
+type DialogsIterator struct {
+	pageCursor
+	items []DialogResponseItem
+}
+
+// Item returns the current item.
+func (it *DialogsIterator) Item() DialogResponseItem {
+	return it.items[it.index]
+}
+
+// IterateDialogs returns iterator over all dialogs matching the request. Request Limit is used as page size.
+func (c *MgClient) IterateDialogs(
+	ctx context.Context, request DialogsRequest, opts ...IterateOption,
+) *DialogsIterator {
+	it := &DialogsIterator{}
+	it.pageCursor = newPageCursor(ctx, request.SinceID, request.Limit, opts,
+		func(ctx context.Context, sinceID uint64) (int, uint64, error) {
+			request.SinceID = sinceID
+
+			items, _, err := c.DialogsContext(ctx, request)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			it.items = items
+			var lastID uint64
+			for _, item := range items {
+				lastID = maxID(lastID, item.ID)
+			}
+
+			return len(items), lastID, nil
+		})
+
+	return it
+}
+
+// MessagesIterator iterates over Messages results. Use Next, Item and Err methods.
+type MessagesIterator struct {
+	pageCursor
+	items []MessagesResponseItem
+}
+
+// Item returns the current item.
+func (it *MessagesIterator) Item() MessagesResponseItem {
+	return it.items[it.index]
+}
+
+// IterateMessages returns iterator over all messages matching the request. Request Limit is used as page size.
+func (c *MgClient) IterateMessages(
+	ctx context.Context, request MessagesRequest, opts ...IterateOption,
+) *MessagesIterator {
+	it := &MessagesIterator{}
+	it.pageCursor = newPageCursor(ctx, request.SinceID, request.Limit, opts,
+		func(ctx context.Context, sinceID uint64) (int, uint64, error) {
+			request.SinceID = sinceID
+
+			items, _, err := c.MessagesContext(ctx, request)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			it.items = items
+			var lastID uint64
+			for _, item := range items {
+				lastID = maxID(lastID, item.ID)
+			}
+
+			return len(items), lastID, nil
+		})
+
+	return it
+}
+
+// CommandsIterator iterates over Commands results. Use Next, Item and Err methods.
+type CommandsIterator struct {
+	pageCursor
+	items []CommandsResponseItem
+}
+
+// Item returns the current item.
+func (it *CommandsIterator) Item() CommandsResponseItem {
+	return it.items[it.index]
+}
+
+// IterateCommands returns iterator over all bot commands matching the request. Request Limit is used as page size.
+func (c *MgClient) IterateCommands(
+	ctx context.Context, request CommandsRequest, opts ...IterateOption,
+) *CommandsIterator {
+	it := &CommandsIterator{}
+	it.pageCursor = newPageCursor(ctx, request.SinceID, request.Limit, opts,
+		func(ctx context.Context, sinceID uint64) (int, uint64, error) {
+			request.SinceID = sinceID
+
+			items, _, err := c.CommandsContext(ctx, request)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			it.items = items
+			var lastID uint64
+			for _, item := range items {
+				lastID = maxID(lastID, item.ID)
+			}
+
+			return len(items), lastID, nil
+		})
+
+	return it
+}
+
+func maxID(a, b uint64) uint64 {
+	if b > a {
+		return b
+	}
+
+	return a
+}
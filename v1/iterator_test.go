@@ -0,0 +1,131 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func paginatedMessagesServer(t *testing.T, total int, failAfter int) (*httptest.Server, *int) {
+	requests := 0
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failAfter > 0 && requests > failAfter {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": ["invalid since_id"]}`))
+			return
+		}
+
+		sinceID, _ := strconv.Atoi(r.URL.Query().Get("since_id"))
+		limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+		require.NoError(t, err)
+
+		items := []MessagesResponseItem{}
+		for id := sinceID + 1; id <= total && len(items) < limit; id++ {
+			items = append(items, MessagesResponseItem{Message: Message{ID: uint64(id)}})
+		}
+
+		_ = json.NewEncoder(w).Encode(items)
+	})), &requests
+}
+
+func TestMgClient_IterateMessages(t *testing.T) {
+	srv, requests := paginatedMessagesServer(t, 5, 0)
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+	it := c.IterateMessages(context.Background(), MessagesRequest{Limit: 2})
+
+	var ids []uint64
+	for it.Next() {
+		ids = append(ids, it.Item().ID)
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5}, ids)
+	assert.Equal(t, 3, *requests)
+}
+
+func TestMgClient_IterateMessagesExactPages(t *testing.T) {
+	srv, requests := paginatedMessagesServer(t, 4, 0)
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+	it := c.IterateMessages(context.Background(), MessagesRequest{Limit: 2, SinceID: 1})
+
+	var ids []uint64
+	for it.Next() {
+		ids = append(ids, it.Item().ID)
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []uint64{2, 3, 4}, ids)
+	assert.Equal(t, 2, *requests)
+}
+
+func TestMgClient_IterateMessagesMaxItems(t *testing.T) {
+	srv, requests := paginatedMessagesServer(t, 10, 0)
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+	it := c.IterateMessages(context.Background(), MessagesRequest{Limit: 2}, IterateMaxItems(3))
+
+	var ids []uint64
+	for it.Next() {
+		ids = append(ids, it.Item().ID)
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []uint64{1, 2, 3}, ids)
+	assert.Equal(t, 2, *requests)
+}
+
+func TestMgClient_IterateMessagesError(t *testing.T) {
+	srv, _ := paginatedMessagesServer(t, 10, 1)
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+	it := c.IterateMessages(context.Background(), MessagesRequest{Limit: 2})
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+
+	assert.Equal(t, 2, count)
+	require.Error(t, it.Err())
+	assert.True(t, IsValidation(it.Err()))
+	assert.False(t, it.Next())
+}
+
+func TestMgClient_IterateCommands(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/bot/v1/my/commands", r.URL.Path)
+
+		if r.URL.Query().Get("since_id") == "" {
+			_, _ = w.Write([]byte(`[{"id": 1, "name": "start"}, {"id": 2, "name": "help"}]`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+	it := c.IterateCommands(context.Background(), CommandsRequest{})
+
+	var names []string
+	for it.Next() {
+		names = append(names, it.Item().Name)
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"start", "help"}, names)
+}
@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// SendLongText sends the text message splitting its content into several messages (see SplitText)
+// to fit the text limit of the chat channel (ChannelSettingsText.MaxCharsCount). The parts are sent in order,
+// QuoteMessageId is applied to the first part only and TransportAttachments to the last part only.
+//
+// It returns responses of all sent messages. If sending of a part fails, responses of the parts sent before
+// are returned with the error.
+//
+// Example:
+//
+//	responses, err := client.SendLongText(ctx, v1.MessageSendRequest{
+//		Type:           v1.MsgTypeText,
+//		Scope:          v1.MessageScopePublic,
+//		ChatID:         12,
+//		Content:        faq,
+//		QuoteMessageId: 345,
+//	})
+func (c *MgClient) SendLongText(ctx context.Context, request MessageSendRequest) ([]MessageSendResponse, error) {
+	settings, err := c.ChatChannelSettings(ctx, request.ChatID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Type == "" {
+		request.Type = MsgTypeText
+	}
+
+	parts := SplitText(request.Content, int(settings.Text.MaxCharsCount))
+	responses := make([]MessageSendResponse, 0, len(parts))
+
+	for i, part := range parts {
+		partRequest := request
+		partRequest.Content = part
+
+		if i > 0 {
+			partRequest.QuoteMessageId = 0
+		}
+
+		if i < len(parts)-1 {
+			partRequest.TransportAttachments = nil
+		}
+
+		resp, _, err := c.MessageSendContext(ctx, partRequest)
+		if err != nil {
+			return responses, err
+		}
+
+		responses = append(responses, resp)
+	}
+
+	return responses, nil
+}
+
+// SplitText splits the text into parts of at most limit characters (runes). The text is split on paragraph,
+// line or sentence boundary if it leaves at least a half of the limit in the part, otherwise on word boundary.
+// A word longer than the limit is split as is. Whitespace around the split points is dropped.
+// Zero or negative limit means no limit.
+func SplitText(text string, limit int) []string {
+	runes := []rune(text)
+	if limit <= 0 || len(runes) <= limit {
+		return []string{text}
+	}
+
+	var parts []string
+	for len(runes) > limit {
+		cut := splitPoint(runes, limit)
+		if part := strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace); part != "" {
+			parts = append(parts, part)
+		}
+
+		runes = trimLeftSpace(runes[cut:])
+	}
+
+	if len(runes) > 0 {
+		parts = append(parts, string(runes))
+	}
+
+	return parts
+}
+
+// splitPoint returns the number of runes which go to the next part.
+func splitPoint(runes []rune, limit int) int {
+	boundaries := []func(runes []rune, i int) bool{
+		isParagraphEnd,
+		isLineEnd,
+		isSentenceEnd,
+	}
+
+	for _, isBoundary := range boundaries {
+		for i := limit; i >= limit/2 && i > 0; i-- {
+			if isBoundary(runes, i) {
+				return i
+			}
+		}
+	}
+
+	for i := limit; i > 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+
+	return limit
+}
+
+// isParagraphEnd returns true if runes[i:] starts with an empty line.
+func isParagraphEnd(runes []rune, i int) bool {
+	return i+1 < len(runes) && runes[i] == '\n' && (runes[i+1] == '\n' || runes[i+1] == '\r')
+}
+
+func isLineEnd(runes []rune, i int) bool {
+	return runes[i] == '\n'
+}
+
+// isSentenceEnd returns true if runes[:i] ends with a sentence.
+func isSentenceEnd(runes []rune, i int) bool {
+	switch runes[i-1] {
+	case '.', '!', '?', '…':
+		return unicode.IsSpace(runes[i])
+	default:
+		return false
+	}
+}
+
+func trimLeftSpace(runes []rune) []rune {
+	for len(runes) > 0 && unicode.IsSpace(runes[0]) {
+		runes = runes[1:]
+	}
+
+	return runes
+}
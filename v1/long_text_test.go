@@ -0,0 +1,131 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitText(t *testing.T) {
+	cases := []struct {
+		name  string
+		text  string
+		limit int
+		parts []string
+	}{
+		{"short", "Hello", 10, []string{"Hello"}},
+		{"no limit", "Hello world", 0, []string{"Hello world"}},
+		{
+			"paragraphs",
+			"First paragraph.\n\nSecond one. Goes on.",
+			25,
+			[]string{"First paragraph.", "Second one. Goes on."},
+		},
+		{
+			"sentences",
+			"One sentence here. Another sentence there.",
+			30,
+			[]string{"One sentence here.", "Another sentence there."},
+		},
+		{
+			"words",
+			"Съешь же ещё этих мягких французских булок",
+			16,
+			[]string{"Съешь же ещё", "этих мягких", "французских", "булок"},
+		},
+		{
+			"long word",
+			"абвгдеёжзий",
+			4,
+			[]string{"абвг", "деёж", "зий"},
+		},
+		{
+			"early sentence end",
+			"Ok. This is a long sentence without breaks",
+			20,
+			[]string{"Ok. This is a long", "sentence without", "breaks"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			parts := SplitText(c.text, c.limit)
+			assert.Equal(t, c.parts, parts)
+
+			for _, part := range parts {
+				assert.True(t, utf8.ValidString(part))
+				if c.limit > 0 {
+					assert.LessOrEqual(t, utf8.RuneCountInString(part), c.limit)
+				}
+			}
+		})
+	}
+}
+
+func TestMgClient_SendLongText(t *testing.T) {
+	var sent []MessageSendRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/bot/v1/chats":
+			assert.Equal(t, "12", r.URL.Query().Get("id"))
+			_, _ = w.Write([]byte(`[{"id": 12, "channel": {"id": 3}}]`))
+		case "/api/bot/v1/channels":
+			assert.Equal(t, "3", r.URL.Query().Get("id"))
+			_, _ = w.Write([]byte(`[{"id": 3, "settings": {"text": {"max_chars_count": 20}}}]`))
+		case "/api/bot/v1/messages":
+			var req MessageSendRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			sent = append(sent, req)
+			_, _ = w.Write([]byte(`{"message_id": ` + strconv.Itoa(len(sent)) + `}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+	responses, err := c.SendLongText(context.Background(), MessageSendRequest{
+		Scope:          MessageScopePublic,
+		ChatID:         12,
+		Content:        "First sentence is here. Second sentence is there.",
+		QuoteMessageId: 100,
+		TransportAttachments: &TransportAttachments{
+			Suggestions: []Suggestion{{Type: SuggestionTypeText, Title: "Thanks"}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, responses, 3)
+	assert.Equal(t, uint64(3), responses[2].MessageID)
+
+	var contents []string
+	for _, req := range sent {
+		assert.Equal(t, MsgTypeText, req.Type)
+		contents = append(contents, req.Content)
+	}
+
+	assert.Equal(t, []string{"First sentence is", "here. Second", "sentence is there."}, contents)
+	assert.Equal(t, uint64(100), sent[0].QuoteMessageId)
+	assert.Zero(t, sent[1].QuoteMessageId)
+	assert.Nil(t, sent[0].TransportAttachments)
+	assert.NotNil(t, sent[2].TransportAttachments)
+	assert.Equal(t, "First sentence is here. Second sentence is there.", strings.Join(contents, " "))
+}
+
+func TestMgClient_SendLongTextChatNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	_, err := New(srv.URL, mgToken).SendLongText(context.Background(), MessageSendRequest{ChatID: 1, Content: "Hi"})
+	assert.True(t, IsNotFound(err))
+}
@@ -0,0 +1,301 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest describes the bot profile and the command set. It is read from YAML or JSON with ParseManifest
+// or LoadManifest and applied with MgClient.Apply:
+//
+//	name: Support bot
+//	avatar: https://example.com/avatar.png
+//	roles: [distributor, responsible]
+//	commands:
+//	  - name: start
+//	    description: Start conversation
+//	  - name: order
+//	    description: Show order status
+//
+// Empty profile fields keep current values. Commands which are not listed are deleted, omit the commands
+// field to leave bot commands as is.
+type Manifest struct {
+	Name     string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Avatar   string            `json:"avatar,omitempty" yaml:"avatar,omitempty"`
+	Roles    []string          `json:"roles,omitempty" yaml:"roles,omitempty"`
+	Commands []ManifestCommand `json:"commands,omitempty" yaml:"commands,omitempty"`
+}
+
+// ManifestCommand is a bot command in Manifest.
+type ManifestCommand struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+}
+
+// ManifestPlan lists changes required to bring the bot in line with Manifest.
+type ManifestPlan struct {
+	// Info is the new bot profile, nil if the profile is up to date.
+	Info *InfoRequest
+	// Current is the bot profile before the changes. It is empty if the manifest does not set profile fields.
+	Current BotsResponseItem
+	// Create and Update list commands to create and to change the description of.
+	Create []CommandEditRequest
+	Update []CommandEditRequest
+	// Delete lists names of commands to delete.
+	Delete []string
+	// descriptions holds current descriptions of updated commands.
+	descriptions map[string]string
+}
+
+// ParseManifest parses YAML or JSON manifest and validates it. Unknown fields are rejected.
+func ParseManifest(data []byte) (Manifest, error) {
+	var manifest Manifest
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&manifest); err != nil {
+		return manifest, fmt.Errorf("%w: invalid manifest: %v", ErrValidation, err)
+	}
+
+	return manifest, manifest.Validate()
+}
+
+// LoadManifest reads the manifest from the YAML or JSON file.
+func LoadManifest(path string) (Manifest, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	return ParseManifest(data)
+}
+
+// Validate checks that commands have names and descriptions and that names are unique.
+// The error matches ErrValidation.
+func (m Manifest) Validate() error {
+	var errs []string
+
+	seen := map[string]bool{}
+	for i, command := range m.Commands {
+		switch {
+		case command.Name == "":
+			errs = append(errs, fmt.Sprintf("commands[%d]: name is required", i))
+		case strings.ContainsAny(command.Name, " /"):
+			errs = append(errs, fmt.Sprintf("command %s: name must not contain spaces and slashes", command.Name))
+		case seen[command.Name]:
+			errs = append(errs, fmt.Sprintf("command %s: duplicate name", command.Name))
+		}
+
+		if command.Description == "" {
+			errs = append(errs, fmt.Sprintf("command %s: description is required", command.Name))
+		}
+
+		seen[command.Name] = true
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %s", ErrValidation, strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// PlanManifest compares the manifest with the current bot profile and commands and returns required changes.
+// Nothing is changed. The bot profile is requested only if the manifest sets profile fields.
+func (c *MgClient) PlanManifest(ctx context.Context, manifest Manifest) (ManifestPlan, error) {
+	var plan ManifestPlan
+	if err := manifest.Validate(); err != nil {
+		return plan, err
+	}
+
+	if manifest.Name != "" || manifest.Avatar != "" || manifest.Roles != nil {
+		bots, _, err := c.BotsContext(ctx, BotsRequest{Self: 1})
+		if err != nil {
+			return plan, err
+		}
+
+		if len(bots) == 0 {
+			return plan, fmt.Errorf("%w: bot", ErrNotFound)
+		}
+
+		plan.Current = bots[0]
+		plan.Info = planInfo(plan.Current, manifest)
+	}
+
+	if manifest.Commands == nil {
+		return plan, nil
+	}
+
+	existing := map[string]string{}
+	it := c.IterateCommands(ctx, CommandsRequest{})
+	for it.Next() {
+		existing[it.Item().Name] = it.Item().Description
+	}
+
+	if err := it.Err(); err != nil {
+		return plan, err
+	}
+
+	plan.descriptions = map[string]string{}
+	for _, command := range manifest.Commands {
+		description, ok := existing[command.Name]
+		delete(existing, command.Name)
+
+		request := CommandEditRequest{Name: command.Name, Description: command.Description}
+		switch {
+		case !ok:
+			plan.Create = append(plan.Create, request)
+		case description != command.Description:
+			plan.Update = append(plan.Update, request)
+			plan.descriptions[command.Name] = description
+		}
+	}
+
+	for name := range existing {
+		plan.Delete = append(plan.Delete, name)
+	}
+
+	sort.Strings(plan.Delete)
+
+	return plan, nil
+}
+
+// ApplyPlan makes changes listed in the plan: updates the bot profile, creates, updates and deletes commands.
+// It stops on the first error.
+func (c *MgClient) ApplyPlan(ctx context.Context, plan ManifestPlan) error {
+	if plan.Info != nil {
+		if _, _, err := c.InfoContext(ctx, *plan.Info); err != nil {
+			return err
+		}
+	}
+
+	for _, request := range append(append([]CommandEditRequest{}, plan.Create...), plan.Update...) {
+		if _, _, err := c.CommandEditContext(ctx, request); err != nil {
+			return fmt.Errorf("command %s: %w", request.Name, err)
+		}
+	}
+
+	for _, name := range plan.Delete {
+		if _, _, err := c.CommandDeleteContext(ctx, name); err != nil {
+			return fmt.Errorf("command %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Apply brings the bot profile and commands in line with the manifest and returns the applied plan.
+// Applying the same manifest again results in an empty plan.
+//
+// Example:
+//
+//	manifest, err := v1.LoadManifest("bot.yaml")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	plan, err := client.Apply(ctx, manifest)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	fmt.Print(plan)
+func (c *MgClient) Apply(ctx context.Context, manifest Manifest) (ManifestPlan, error) {
+	plan, err := c.PlanManifest(ctx, manifest)
+	if err != nil {
+		return plan, err
+	}
+
+	return plan, c.ApplyPlan(ctx, plan)
+}
+
+// Empty reports whether the plan has no changes.
+func (p ManifestPlan) Empty() bool {
+	return p.Info == nil && len(p.Create) == 0 && len(p.Update) == 0 && len(p.Delete) == 0
+}
+
+// String returns the plan in human readable form, one change per line:
+// "+" marks created commands, "~" changed values and "-" deleted commands.
+func (p ManifestPlan) String() string {
+	if p.Empty() {
+		return "No changes.\n"
+	}
+
+	var b strings.Builder
+	if p.Info != nil {
+		writeChange(&b, "name", p.Current.Name, p.Info.Name)
+		writeChange(&b, "avatar", p.Current.AvatarUrl, p.Info.Avatar)
+		writeChange(&b, "roles", strings.Join(p.Current.Roles, ", "), strings.Join(p.Info.Roles, ", "))
+	}
+
+	for _, command := range p.Create {
+		fmt.Fprintf(&b, "+ /%s: %q\n", command.Name, command.Description)
+	}
+
+	for _, command := range p.Update {
+		fmt.Fprintf(&b, "~ /%s: %q -> %q\n", command.Name, p.descriptions[command.Name], command.Description)
+	}
+
+	for _, name := range p.Delete {
+		fmt.Fprintf(&b, "- /%s\n", name)
+	}
+
+	return b.String()
+}
+
+// planInfo returns the new bot profile or nil if the manifest does not change it.
+// InfoRequest sends all fields, so the fields which are not set in the manifest keep current values.
+func planInfo(bot BotsResponseItem, manifest Manifest) *InfoRequest {
+	info := InfoRequest{Name: bot.Name, Avatar: bot.AvatarUrl, Roles: bot.Roles}
+	changed := false
+
+	if manifest.Name != "" && manifest.Name != bot.Name {
+		info.Name, changed = manifest.Name, true
+	}
+
+	if manifest.Avatar != "" && manifest.Avatar != bot.AvatarUrl {
+		info.Avatar, changed = manifest.Avatar, true
+	}
+
+	if manifest.Roles != nil && !sameStrings(manifest.Roles, bot.Roles) {
+		info.Roles, changed = manifest.Roles, true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return &info
+}
+
+func writeChange(b *strings.Builder, field, current, value string) {
+	if current != value {
+		fmt.Fprintf(b, "~ %s: %q -> %q\n", field, current, value)
+	}
+}
+
+// sameStrings reports whether slices contain the same strings regardless of order.
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManifest(t *testing.T) {
+	yamlManifest, err := ParseManifest([]byte(`
+name: Support bot
+roles: [distributor, responsible]
+commands:
+  - name: start
+    description: Start conversation
+`))
+	require.NoError(t, err)
+
+	jsonManifest, err := ParseManifest([]byte(`{
+		"name": "Support bot",
+		"roles": ["distributor", "responsible"],
+		"commands": [{"name": "start", "description": "Start conversation"}]
+	}`))
+	require.NoError(t, err)
+
+	expected := Manifest{
+		Name:     "Support bot",
+		Roles:    []string{"distributor", "responsible"},
+		Commands: []ManifestCommand{{Name: "start", Description: "Start conversation"}},
+	}
+	assert.Equal(t, expected, yamlManifest)
+	assert.Equal(t, expected, jsonManifest)
+
+	manifest, err := ParseManifest([]byte("name: Support bot\ncommands: []\n"))
+	require.NoError(t, err)
+	assert.NotNil(t, manifest.Commands)
+
+	manifest, err = ParseManifest([]byte("name: Support bot\n"))
+	require.NoError(t, err)
+	assert.Nil(t, manifest.Commands)
+
+	_, err = ParseManifest([]byte("title: Support bot\n"))
+	assert.True(t, IsValidation(err))
+
+	assert.NotPanics(t, func() {
+		_, err = ParseManifest([]byte("0: [:!00 \xef"))
+	})
+	assert.True(t, IsValidation(err))
+
+	_, err = ParseManifest([]byte(`
+commands:
+  - name: start
+    description: Start
+  - name: start
+    description: Start again
+  - name: "order status"
+  - description: Help
+`))
+	assert.True(t, IsValidation(err))
+	assert.Contains(t, err.Error(), "command start: duplicate name")
+	assert.Contains(t, err.Error(), "command order status: name must not contain spaces and slashes")
+	assert.Contains(t, err.Error(), "command order status: description is required")
+	assert.Contains(t, err.Error(), "commands[3]: name is required")
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "bot.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("name: Support bot\n"), 0600))
+
+	manifest, err := LoadManifest(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Support bot", manifest.Name)
+
+	_, err = LoadManifest(filepath.Join(dir, "missing.yaml"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestMgClient_Apply(t *testing.T) {
+	var (
+		info    []InfoRequest
+		edited  []string
+		deleted []string
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/bot/v1/bots":
+			assert.Equal(t, "1", r.URL.Query().Get("self"))
+			_, _ = w.Write([]byte(`[{"id": 1, "name": "Bot", "avatar_url": "https://example.com/a.png",
+				"roles": ["responsible", "distributor"], "is_self": true}]`))
+		case r.URL.Path == "/api/bot/v1/my/info":
+			var req InfoRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			info = append(info, req)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodGet:
+			if r.URL.Query().Get("since_id") != "" {
+				_, _ = w.Write([]byte(`[]`))
+				return
+			}
+
+			_, _ = w.Write([]byte(`[
+				{"id": 1, "name": "start", "description": "Start"},
+				{"id": 2, "name": "help", "description": "Old help"},
+				{"id": 3, "name": "stale", "description": "Stale"}
+			]`))
+		case r.Method == http.MethodPut:
+			var req CommandEditRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			edited = append(edited, req.Name+":"+req.Description)
+			_, _ = w.Write([]byte(`{}`))
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := New(srv.URL, mgToken)
+	manifest := Manifest{
+		Name:  "Support bot",
+		Roles: []string{"distributor", "responsible"},
+		Commands: []ManifestCommand{
+			{Name: "start", Description: "Start"},
+			{Name: "help", Description: "Help"},
+			{Name: "order", Description: "Order status"},
+		},
+	}
+
+	plan, err := client.PlanManifest(context.Background(), manifest)
+	require.NoError(t, err)
+	assert.Empty(t, edited)
+	assert.Equal(t, "~ name: \"Bot\" -> \"Support bot\"\n"+
+		"+ /order: \"Order status\"\n"+
+		"~ /help: \"Old help\" -> \"Help\"\n"+
+		"- /stale\n", plan.String())
+
+	plan, err = client.Apply(context.Background(), manifest)
+	require.NoError(t, err)
+	assert.False(t, plan.Empty())
+	assert.Equal(t, []InfoRequest{{
+		Name: "Support bot", Avatar: "https://example.com/a.png", Roles: []string{"responsible", "distributor"},
+	}}, info)
+	assert.Equal(t, []string{"order:Order status", "help:Help"}, edited)
+	assert.Equal(t, []string{"/api/bot/v1/my/commands/stale"}, deleted)
+
+	plan, err = client.PlanManifest(context.Background(), Manifest{Roles: []string{"responsible", "distributor"}})
+	require.NoError(t, err)
+	assert.True(t, plan.Empty())
+	assert.Equal(t, "No changes.\n", plan.String())
+
+	_, err = client.Apply(context.Background(), Manifest{Commands: []ManifestCommand{{Name: "start"}}})
+	assert.True(t, IsValidation(err))
+}
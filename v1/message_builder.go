@@ -0,0 +1,449 @@
+package v1
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	knownOrderStatuses = []string{
+		MsgOrderStatusCodeNew,
+		MsgOrderStatusCodeApproval,
+		MsgOrderStatusCodeAssembling,
+		MsgOrderStatusCodeDelivery,
+		MsgOrderStatusCodeComplete,
+		MsgOrderStatusCodeCancel,
+	}
+
+	knownCurrencies = []string{
+		MsgCurrencyRub,
+		MsgCurrencyUah,
+		MsgCurrencyByr,
+		MsgCurrencyKzt,
+		MsgCurrencyUsd,
+		MsgCurrencyEur,
+	}
+)
+
+// OrderMessageBuilder builds MsgTypeOrder MessageSendRequest. All money values use the currency set by Currency.
+// Amounts and quantities are Decimal values, use NewDecimal or NewDecimalFromMinorUnits to convert numbers.
+//
+// Example:
+//
+//	request, err := v1.NewOrderMessage(chatID).
+//		Number("1234C").
+//		Status(v1.MsgOrderStatusCodeNew, "New").
+//		Currency(v1.MsgCurrencyRub).
+//		Cost("1300").
+//		AddItem("T-shirt", "2", "pcs", "500").
+//		Delivery("Courier", "300", "Moscow, Kremlin").
+//		Payment("Card", "1300", "Paid", true).
+//		Build()
+//	if err != nil {
+//		return err
+//	}
+//
+//	data, status, err := client.MessageSend(request)
+type OrderMessageBuilder struct {
+	chatID   uint64
+	scope    string
+	currency string
+	order    MessageOrder
+	errs     []string
+}
+
+// NewOrderMessage returns the order message builder for the chat. The message scope is public by default.
+func NewOrderMessage(chatID uint64) *OrderMessageBuilder {
+	return &OrderMessageBuilder{chatID: chatID, scope: MessageScopePublic}
+}
+
+// Scope sets the message scope.
+func (b *OrderMessageBuilder) Scope(scope string) *OrderMessageBuilder {
+	b.scope = scope
+	return b
+}
+
+// Number sets the order number. It is required.
+func (b *OrderMessageBuilder) Number(number string) *OrderMessageBuilder {
+	b.order.Number = number
+	return b
+}
+
+// URL sets the order page URL.
+func (b *OrderMessageBuilder) URL(url string) *OrderMessageBuilder {
+	b.order.Url = url
+	return b
+}
+
+// Date sets the order date.
+func (b *OrderMessageBuilder) Date(date string) *OrderMessageBuilder {
+	b.order.Date = date
+	return b
+}
+
+// Status sets the order status. It is required, code must be one of MsgOrderStatusCode* constants.
+func (b *OrderMessageBuilder) Status(code, name string) *OrderMessageBuilder {
+	if !contains(knownOrderStatuses, code) {
+		b.errs = append(b.errs, fmt.Sprintf("unknown order status code %q", code))
+	}
+
+	b.order.Status = &MessageOrderStatus{Code: code, Name: name}
+
+	return b
+}
+
+// Currency sets the currency of all money values. It must be one of MsgCurrency* constants.
+func (b *OrderMessageBuilder) Currency(currency string) *OrderMessageBuilder {
+	b.currency = currency
+	return b
+}
+
+// Cost sets the total order cost.
+func (b *OrderMessageBuilder) Cost(value Decimal) *OrderMessageBuilder {
+	b.order.Cost = b.money(value)
+	return b
+}
+
+// AddItem adds the order item.
+func (b *OrderMessageBuilder) AddItem(name string, quantity Decimal, unit string, price Decimal) *OrderMessageBuilder {
+	return b.AddOrderItem(MessageOrderItem{
+		Name:     name,
+		Quantity: &MessageOrderQuantity{Value: quantity, Unit: unit},
+		Price:    b.money(price),
+	})
+}
+
+// AddOrderItem adds the order item with URL, image and other fields set by the caller.
+// The builder currency is used if the item price currency is empty.
+func (b *OrderMessageBuilder) AddOrderItem(item MessageOrderItem) *OrderMessageBuilder {
+	if item.Name == "" {
+		b.errs = append(b.errs, fmt.Sprintf("item #%d: name is required", len(b.order.Items)+1))
+	}
+
+	if item.Quantity != nil {
+		for _, err := range validateQuantity(item.Quantity.Value) {
+			b.errs = append(b.errs, fmt.Sprintf("item #%d: %s", len(b.order.Items)+1, err))
+		}
+	}
+
+	if item.Price != nil {
+		price := *item.Price
+		item.Price = &price
+	}
+
+	b.order.Items = append(b.order.Items, item)
+
+	return b
+}
+
+// Delivery sets the order delivery.
+func (b *OrderMessageBuilder) Delivery(name string, price Decimal, address string) *OrderMessageBuilder {
+	if name == "" {
+		b.errs = append(b.errs, "delivery: name is required")
+	}
+
+	b.order.Delivery = &MessageOrderDelivery{Name: name, Price: b.money(price), Address: address}
+
+	return b
+}
+
+// DeliveryComment sets the comment of the order delivery. Call it after Delivery.
+func (b *OrderMessageBuilder) DeliveryComment(comment string) *OrderMessageBuilder {
+	if b.order.Delivery == nil {
+		b.errs = append(b.errs, "delivery: comment is set before delivery")
+		return b
+	}
+
+	b.order.Delivery.Comment = comment
+
+	return b
+}
+
+// Payment adds the order payment.
+func (b *OrderMessageBuilder) Payment(name string, amount Decimal, status string, payed bool) *OrderMessageBuilder {
+	if name == "" {
+		b.errs = append(b.errs, fmt.Sprintf("payment #%d: name is required", len(b.order.Payments)+1))
+	}
+
+	b.order.Payments = append(b.order.Payments, MessageOrderPayment{
+		Name:   name,
+		Status: &MessageOrderPaymentStatus{Name: status, Payed: payed},
+		Amount: b.money(amount),
+	})
+
+	return b
+}
+
+// Build validates the order and returns the request ready to send.
+// The error matches ErrValidation and lists all problems found.
+func (b *OrderMessageBuilder) Build() (MessageSendRequest, error) {
+	errs := append([]string(nil), b.errs...)
+	if b.chatID == 0 {
+		errs = append(errs, "chat id is required")
+	}
+
+	if b.order.Number == "" {
+		errs = append(errs, "order number is required")
+	}
+
+	if b.order.Status == nil {
+		errs = append(errs, "order status is required")
+	}
+
+	// The request gets its own copy, so the builder can be changed and built again.
+	order := copyOrder(b.order)
+	checked := map[string]bool{}
+	for _, cost := range orderCosts(&order) {
+		if cost.Currency == "" {
+			cost.Currency = b.currency
+		}
+
+		errs = append(errs, validateDecimal(cost.Value)...)
+
+		if !checked[cost.Currency] {
+			checked[cost.Currency] = true
+			errs = append(errs, validateCurrency(cost.Currency)...)
+		}
+	}
+
+	request := MessageSendRequest{
+		Type:   MsgTypeOrder,
+		Scope:  b.scope,
+		ChatID: b.chatID,
+		Order:  &order,
+	}
+
+	return request, buildError(errs)
+}
+
+// money returns the cost in the builder currency. The currency is filled by Build if it is set later.
+func (b *OrderMessageBuilder) money(value Decimal) *MessageOrderCost {
+	return &MessageOrderCost{Value: value, Currency: b.currency}
+}
+
+// orderCosts returns all money values of the order.
+func orderCosts(order *MessageOrder) []*MessageOrderCost {
+	costs := []*MessageOrderCost{order.Cost}
+	if order.Delivery != nil {
+		costs = append(costs, order.Delivery.Price)
+	}
+
+	for _, item := range order.Items {
+		costs = append(costs, item.Price)
+	}
+
+	for _, payment := range order.Payments {
+		costs = append(costs, payment.Amount)
+	}
+
+	result := costs[:0]
+	for _, cost := range costs {
+		if cost != nil {
+			result = append(result, cost)
+		}
+	}
+
+	return result
+}
+
+// copyOrder returns the deep copy of the order.
+func copyOrder(order MessageOrder) MessageOrder {
+	order.Cost = copyCost(order.Cost)
+	if order.Status != nil {
+		status := *order.Status
+		order.Status = &status
+	}
+
+	if order.Delivery != nil {
+		delivery := *order.Delivery
+		delivery.Price = copyCost(delivery.Price)
+		order.Delivery = &delivery
+	}
+
+	items := order.Items
+	order.Items = nil
+	for _, item := range items {
+		item.Quantity = copyQuantity(item.Quantity)
+		item.Price = copyCost(item.Price)
+		order.Items = append(order.Items, item)
+	}
+
+	payments := order.Payments
+	order.Payments = nil
+	for _, payment := range payments {
+		if payment.Status != nil {
+			status := *payment.Status
+			payment.Status = &status
+		}
+
+		payment.Amount = copyCost(payment.Amount)
+		order.Payments = append(order.Payments, payment)
+	}
+
+	return order
+}
+
+func copyCost(cost *MessageOrderCost) *MessageOrderCost {
+	if cost == nil {
+		return nil
+	}
+
+	c := *cost
+	return &c
+}
+
+func copyQuantity(quantity *MessageOrderQuantity) *MessageOrderQuantity {
+	if quantity == nil {
+		return nil
+	}
+
+	q := *quantity
+	return &q
+}
+
+// ProductMessageBuilder builds MsgTypeProduct MessageSendRequest.
+//
+// Example:
+//
+//	request, err := v1.NewProductMessage(chatID, 12, "T-shirt").
+//		Article("TS-01").
+//		URL("https://example.com/t-shirt").
+//		Image("https://example.com/t-shirt.jpg").
+//		Cost("500", v1.MsgCurrencyRub).
+//		Quantity("1", "pcs").
+//		Build()
+type ProductMessageBuilder struct {
+	chatID  uint64
+	scope   string
+	product MessageProduct
+	errs    []string
+}
+
+// NewProductMessage returns the product message builder for the chat. The message scope is public by default.
+func NewProductMessage(chatID uint64, productID uint64, name string) *ProductMessageBuilder {
+	return &ProductMessageBuilder{
+		chatID:  chatID,
+		scope:   MessageScopePublic,
+		product: MessageProduct{ID: productID, Name: name},
+	}
+}
+
+// Scope sets the message scope.
+func (b *ProductMessageBuilder) Scope(scope string) *ProductMessageBuilder {
+	b.scope = scope
+	return b
+}
+
+// Article sets the product article.
+func (b *ProductMessageBuilder) Article(article string) *ProductMessageBuilder {
+	b.product.Article = article
+	return b
+}
+
+// URL sets the product page URL.
+func (b *ProductMessageBuilder) URL(url string) *ProductMessageBuilder {
+	b.product.Url = url
+	return b
+}
+
+// Image sets the product image URL.
+func (b *ProductMessageBuilder) Image(url string) *ProductMessageBuilder {
+	b.product.Img = url
+	return b
+}
+
+// Cost sets the product cost. Currency must be one of MsgCurrency* constants.
+func (b *ProductMessageBuilder) Cost(value Decimal, currency string) *ProductMessageBuilder {
+	b.product.Cost = &MessageOrderCost{Value: value, Currency: currency}
+	b.errs = append(b.errs, validateCurrency(currency)...)
+	b.errs = append(b.errs, validateDecimal(b.product.Cost.Value)...)
+
+	return b
+}
+
+// Quantity sets the product quantity.
+func (b *ProductMessageBuilder) Quantity(value Decimal, unit string) *ProductMessageBuilder {
+	b.product.Quantity = &MessageOrderQuantity{Value: value, Unit: unit}
+	b.errs = append(b.errs, validateQuantity(value)...)
+
+	return b
+}
+
+// Build validates the product and returns the request ready to send.
+// The error matches ErrValidation and lists all problems found.
+func (b *ProductMessageBuilder) Build() (MessageSendRequest, error) {
+	errs := append([]string(nil), b.errs...)
+	if b.chatID == 0 {
+		errs = append(errs, "chat id is required")
+	}
+
+	if b.product.ID == 0 {
+		errs = append(errs, "product id is required")
+	}
+
+	if b.product.Name == "" {
+		errs = append(errs, "product name is required")
+	}
+
+	product := b.product
+	product.Cost = copyCost(product.Cost)
+	product.Quantity = copyQuantity(product.Quantity)
+	request := MessageSendRequest{
+		Type:    MsgTypeProduct,
+		Scope:   b.scope,
+		ChatID:  b.chatID,
+		Product: &product,
+	}
+
+	return request, buildError(errs)
+}
+
+func validateCurrency(currency string) []string {
+	if currency == "" {
+		return []string{"currency is required"}
+	}
+
+	if !contains(knownCurrencies, currency) {
+		return []string{fmt.Sprintf("unknown currency %q", currency)}
+	}
+
+	return nil
+}
+
+func validateDecimal(value Decimal) []string {
+	if _, err := ParseDecimal(value.String()); err != nil {
+		return []string{err.Error()}
+	}
+
+	return nil
+}
+
+func validateQuantity(value Decimal) []string {
+	if errs := validateDecimal(value); len(errs) > 0 {
+		return errs
+	}
+
+	if value.Sign() <= 0 {
+		return []string{"quantity must be positive"}
+	}
+
+	return nil
+}
+
+func buildError(errs []string) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrValidation, strings.Join(errs, "; "))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
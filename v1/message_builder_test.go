@@ -0,0 +1,168 @@
+package v1
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderMessageBuilder(t *testing.T) {
+	request, err := NewOrderMessage(12).
+		Number("1234C").
+		URL("https://example.com/orders/1234C").
+		Status(MsgOrderStatusCodeNew, "New").
+		Currency(MsgCurrencyRub).
+		Cost("1300").
+		AddItem("T-shirt", "2", "pcs", "500").
+		AddOrderItem(MessageOrderItem{
+			Name:  "Cap",
+			Img:   "https://example.com/cap.jpg",
+			Price: &MessageOrderCost{Value: "3", Currency: MsgCurrencyUsd},
+		}).
+		Delivery("Courier", "300", "Moscow").
+		DeliveryComment("Call before").
+		Payment("Card", "1300", "Paid", true).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, MsgTypeOrder, request.Type)
+	assert.Equal(t, MessageScopePublic, request.Scope)
+	assert.Equal(t, uint64(12), request.ChatID)
+
+	order := request.Order
+	require.NotNil(t, order)
+	assert.Equal(t, "1234C", order.Number)
+	assert.Equal(t, &MessageOrderStatus{Code: MsgOrderStatusCodeNew, Name: "New"}, order.Status)
+	assert.Equal(t, &MessageOrderCost{Value: "1300", Currency: MsgCurrencyRub}, order.Cost)
+	require.Len(t, order.Items, 2)
+	assert.Equal(t, &MessageOrderQuantity{Value: "2", Unit: "pcs"}, order.Items[0].Quantity)
+	assert.Equal(t, MsgCurrencyRub, order.Items[0].Price.Currency)
+	assert.Equal(t, MsgCurrencyUsd, order.Items[1].Price.Currency)
+	assert.Equal(t, "Call before", order.Delivery.Comment)
+	assert.Equal(t, MsgCurrencyRub, order.Delivery.Price.Currency)
+	require.Len(t, order.Payments, 1)
+	assert.True(t, order.Payments[0].Status.Payed)
+
+	_, err = json.Marshal(request)
+	assert.NoError(t, err)
+}
+
+func TestOrderMessageBuilder_CurrencySetLater(t *testing.T) {
+	request, err := NewOrderMessage(12).
+		Number("1").
+		Status(MsgOrderStatusCodeNew, "New").
+		Cost("100").
+		Currency(MsgCurrencyEur).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, MsgCurrencyEur, request.Order.Cost.Currency)
+}
+
+func TestOrderMessageBuilder_Validation(t *testing.T) {
+	_, err := NewOrderMessage(0).
+		Status("shipped", "Shipped").
+		Cost("100").
+		AddItem("", "0", "pcs", "10").
+		Payment("Card", "100", "", false).
+		Build()
+	require.Error(t, err)
+	assert.True(t, IsValidation(err))
+	assert.Equal(t, `validation error: unknown order status code "shipped"; item #1: name is required; `+
+		`item #1: quantity must be positive; chat id is required; order number is required; currency is required`,
+		err.Error())
+
+	order := func() *OrderMessageBuilder {
+		return NewOrderMessage(1).Number("1").Status(MsgOrderStatusCodeNew, "New")
+	}
+
+	_, err = order().Currency("rur").Cost("1").Build()
+	assert.EqualError(t, err, `validation error: unknown currency "rur"`)
+
+	_, err = order().Currency(MsgCurrencyRub).Cost(NewDecimal(math.Inf(1))).Build()
+	assert.EqualError(t, err, `validation error: invalid decimal "+Inf"`)
+
+	_, err = order().Currency(MsgCurrencyRub).AddItem("Cap", "1,5", "pcs", "10").Build()
+	assert.EqualError(t, err, `validation error: item #1: invalid decimal "1,5"`)
+
+	_, err = NewOrderMessage(1).Number("1").Build()
+	assert.EqualError(t, err, `validation error: order status is required`)
+
+	_, err = order().Build()
+	assert.NoError(t, err)
+}
+
+func TestOrderMessageBuilder_Reuse(t *testing.T) {
+	builder := NewOrderMessage(12).
+		Number("1").
+		Status(MsgOrderStatusCodeNew, "New").
+		Cost("100").
+		AddItem("Cap", "1", "pcs", "10").
+		Currency(MsgCurrencyRub)
+
+	first, err := builder.Build()
+	require.NoError(t, err)
+
+	second, err := builder.Currency(MsgCurrencyEur).Build()
+	require.NoError(t, err)
+	assert.Equal(t, MsgCurrencyRub, first.Order.Cost.Currency)
+	assert.Equal(t, MsgCurrencyRub, first.Order.Items[0].Price.Currency)
+	assert.Equal(t, MsgCurrencyEur, second.Order.Cost.Currency)
+	assert.Equal(t, MsgCurrencyEur, second.Order.Items[0].Price.Currency)
+
+	first.Order.Cost.Value = "1"
+	first.Order.Items[0].Quantity.Value = "5"
+	first.Order.Status.Code = MsgOrderStatusCodeCancel
+
+	third, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, Decimal("100"), third.Order.Cost.Value)
+	assert.Equal(t, Decimal("1"), third.Order.Items[0].Quantity.Value)
+	assert.Equal(t, MsgOrderStatusCodeNew, third.Order.Status.Code)
+}
+
+func TestOrderMessageBuilder_ExactMoney(t *testing.T) {
+	request, err := NewOrderMessage(12).
+		Number("1").
+		Status(MsgOrderStatusCodeNew, "New").
+		Currency(MsgCurrencyRub).
+		Cost(NewDecimal(19999.99)).
+		AddItem("Laptop", "0.35", "kg", NewDecimalFromMinorUnits(1999999, 2)).
+		Build()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(request.Order)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"cost":{"value":19999.99,"currency":"rub"}`)
+	assert.Contains(t, string(data), `"quantity":{"value":0.35,"unit":"kg"}`)
+}
+
+func TestProductMessageBuilder(t *testing.T) {
+	request, err := NewProductMessage(12, 5, "T-shirt").
+		Article("TS-01").
+		URL("https://example.com/t-shirt").
+		Image("https://example.com/t-shirt.jpg").
+		Cost("500", MsgCurrencyRub).
+		Quantity("1", "pcs").
+		Scope(MessageScopePrivate).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, MsgTypeProduct, request.Type)
+	assert.Equal(t, MessageScopePrivate, request.Scope)
+	assert.Equal(t, &MessageProduct{
+		ID:       5,
+		Name:     "T-shirt",
+		Article:  "TS-01",
+		Url:      "https://example.com/t-shirt",
+		Img:      "https://example.com/t-shirt.jpg",
+		Cost:     &MessageOrderCost{Value: "500", Currency: MsgCurrencyRub},
+		Quantity: &MessageOrderQuantity{Value: "1", Unit: "pcs"},
+	}, request.Product)
+
+	_, err = NewProductMessage(12, 0, "").Cost("1", "").Quantity("-1", "pcs").Build()
+	assert.EqualError(t, err, "validation error: currency is required; quantity must be positive; "+
+		"product id is required; product name is required")
+}
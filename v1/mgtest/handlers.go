@@ -0,0 +1,993 @@
+package mgtest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+const (
+	apiPrefix    = "/api/bot/v1"
+	fileDataPath = "/files-data/"
+	defaultLimit = 20
+	senderBot    = "bot"
+)
+
+type route struct {
+	method  string
+	path    []string
+	handler func(s *Server, w http.ResponseWriter, r *http.Request, params []string)
+}
+
+// routes lists implemented endpoints. "*" matches any path segment and is passed to the handler as a parameter.
+var routes = []route{
+	{http.MethodGet, []string{"bots"}, (*Server).listBots},
+	{http.MethodGet, []string{"channels"}, (*Server).listChannels},
+	{http.MethodGet, []string{"users"}, (*Server).listUsers},
+	{http.MethodGet, []string{"customers"}, (*Server).listCustomers},
+	{http.MethodGet, []string{"chats"}, (*Server).listChats},
+	{http.MethodGet, []string{"members"}, (*Server).listMembers},
+	{http.MethodGet, []string{"dialogs"}, (*Server).listDialogs},
+	{http.MethodPatch, []string{"dialogs", "*", "assign"}, (*Server).dialogAssign},
+	{http.MethodPatch, []string{"dialogs", "*", "unassign"}, (*Server).dialogUnassign},
+	{http.MethodDelete, []string{"dialogs", "*", "close"}, (*Server).dialogClose},
+	{http.MethodPatch, []string{"dialogs", "*", "tags", "add"}, (*Server).dialogTagsAdd},
+	{http.MethodPatch, []string{"dialogs", "*", "tags", "delete"}, (*Server).dialogTagsDelete},
+	{http.MethodGet, []string{"messages"}, (*Server).listMessages},
+	{http.MethodPost, []string{"messages"}, (*Server).sendMessage},
+	{http.MethodPatch, []string{"messages", "*"}, (*Server).editMessage},
+	{http.MethodDelete, []string{"messages", "*"}, (*Server).deleteMessage},
+	{http.MethodPatch, []string{"my", "info"}, (*Server).updateInfo},
+	{http.MethodGet, []string{"my", "commands"}, (*Server).listCommands},
+	{http.MethodPut, []string{"my", "commands", "*"}, (*Server).editCommand},
+	{http.MethodDelete, []string{"my", "commands", "*"}, (*Server).deleteCommand},
+	{http.MethodPost, []string{"files", "upload"}, (*Server).uploadFile},
+	{http.MethodPost, []string{"files", "upload_by_url"}, (*Server).uploadFileByURL},
+	{http.MethodGet, []string{"files", "*"}, (*Server).getFile},
+	{http.MethodPut, []string{"files", "*", "meta"}, (*Server).updateFileMeta},
+	{http.MethodGet, []string{"ws"}, (*Server).serveWs},
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, fileDataPath) {
+		s.fileData(w, r)
+		return
+	}
+
+	if !strings.HasPrefix(r.URL.Path, apiPrefix+"/") {
+		writeErrors(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	if r.Header.Get("X-Bot-Token") != Token {
+		writeErrors(w, http.StatusUnauthorized, "Invalid token")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, apiPrefix), "/"), "/")
+	for _, rt := range routes {
+		if params, ok := rt.match(r.Method, parts); ok {
+			rt.handler(s, w, r, params)
+			return
+		}
+	}
+
+	writeErrors(w, http.StatusNotFound, "Not found")
+}
+
+func (rt route) match(method string, parts []string) ([]string, bool) {
+	if rt.method != method || len(rt.path) != len(parts) {
+		return nil, false
+	}
+
+	var params []string
+	for i, segment := range rt.path {
+		switch {
+		case segment == "*":
+			params = append(params, parts[i])
+		case segment != parts[i]:
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+func (s *Server) listBots(w http.ResponseWriter, r *http.Request, _ []string) {
+	q, ok := newListQuery(w, r)
+	if !ok {
+		return
+	}
+	result := []v1.BotsResponseItem{}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, bot := range s.bots {
+		if q.full(len(result)) {
+			break
+		}
+
+		if q.match(bot.ID, bot.CreatedAt) && q.flag("active", bot.IsActive) && q.flag("self", bot.IsSelf) &&
+			q.contains("role", bot.Roles) {
+			result = append(result, bot)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) listChannels(w http.ResponseWriter, r *http.Request, _ []string) {
+	q, ok := newListQuery(w, r)
+	if !ok {
+		return
+	}
+	result := []v1.ChannelResponseItem{}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, channel := range s.channels {
+		if q.full(len(result)) {
+			break
+		}
+
+		if q.match(channel.ID, channel.CreatedAt) && q.flag("active", channel.IsActive) && q.oneOf("types", channel.Type) {
+			result = append(result, channel)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) listUsers(w http.ResponseWriter, r *http.Request, _ []string) {
+	q, ok := newListQuery(w, r)
+	if !ok {
+		return
+	}
+	result := []v1.UsersResponseItem{}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.users {
+		if q.full(len(result)) {
+			break
+		}
+
+		if q.match(user.ID, user.CreatedAt) && q.equal("external_id", user.ExternalID) &&
+			q.flag("online", user.IsOnline) && q.flag("active", user.IsActive) {
+			result = append(result, user)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) listCustomers(w http.ResponseWriter, r *http.Request, _ []string) {
+	q, ok := newListQuery(w, r)
+	if !ok {
+		return
+	}
+	result := []v1.CustomersResponseItem{}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, customer := range s.customers {
+		if q.full(len(result)) {
+			break
+		}
+
+		if q.match(customer.ID, customer.CreatedAt) && q.equalID("channel_id", customer.ChannelId) &&
+			q.equal("external_id", customer.ExternalID) {
+			result = append(result, customer)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) listChats(w http.ResponseWriter, r *http.Request, _ []string) {
+	q, ok := newListQuery(w, r)
+	if !ok {
+		return
+	}
+	result := []v1.ChatResponseItem{}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, chat := range s.chats {
+		if q.full(len(result)) {
+			break
+		}
+
+		if q.match(chat.ID, chat.CreatedAt) && q.equalID("channel_id", chat.Channel.ID) &&
+			q.equal("channel_type", chat.Channel.Type) && q.equalID("customer_id", chat.Customer.ID) &&
+			q.equal("customer_external_id", chat.Customer.ExternalID) {
+			result = append(result, chat)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) listMembers(w http.ResponseWriter, r *http.Request, _ []string) {
+	q, ok := newListQuery(w, r)
+	if !ok {
+		return
+	}
+	result := []v1.MemberResponseItem{}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, member := range s.members {
+		if q.full(len(result)) {
+			break
+		}
+
+		if q.match(member.ID, member.CreatedAt) && q.equalID("chat_id", member.ChatID) &&
+			q.equalID("user_id", member.UserID) && q.equal("state", member.State) {
+			result = append(result, member)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) listDialogs(w http.ResponseWriter, r *http.Request, _ []string) {
+	q, ok := newListQuery(w, r)
+	if !ok {
+		return
+	}
+	result := []v1.DialogResponseItem{}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, dialog := range s.dialogs {
+		if q.full(len(result)) {
+			break
+		}
+
+		if q.match(dialog.ID, dialog.CreatedAt) && q.equalID("chat_id", dialog.ChatID) && q.equalID("bot_id", dialog.BotID) &&
+			q.flag("active", dialog.IsActive) && q.flag("assign", dialog.IsAssigned) {
+			result = append(result, dialog)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) dialogAssign(w http.ResponseWriter, r *http.Request, params []string) {
+	var req v1.DialogAssignRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	dialog := s.dialog(parseID(params[0]))
+	if dialog == nil {
+		s.mu.Unlock()
+		writeErrors(w, http.StatusNotFound, "Dialog not found")
+		return
+	}
+
+	responsible := v1.Responsible{ID: int64(req.UserID), Type: "user", AssignAt: now()}
+	if req.BotID != 0 {
+		responsible = v1.Responsible{ID: int64(req.BotID), Type: senderBot, AssignAt: now()}
+	}
+
+	resp := v1.DialogAssignResponse{
+		Responsible:         responsible,
+		PreviousResponsible: dialog.Responsible,
+		IsReAssign:          dialog.IsAssigned,
+	}
+	dialog.Responsible = responsible
+	dialog.IsAssigned = true
+	dialog.UpdatedAt = now()
+	data := v1.WsEventDialogAssignData{
+		Dialog: &v1.Dialog{ID: dialog.ID, Responsible: &responsible, CreatedAt: dialog.CreatedAt},
+		Chat:   &v1.Chat{ID: dialog.ChatID},
+	}
+	s.mu.Unlock()
+
+	s.Emit(v1.WsEventDialogAssign, data)
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) dialogUnassign(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dialog := s.dialog(parseID(params[0]))
+	if dialog == nil {
+		writeErrors(w, http.StatusNotFound, "Dialog not found")
+		return
+	}
+
+	if !dialog.IsAssigned {
+		writeErrors(w, http.StatusBadRequest, "Dialog is not assigned")
+		return
+	}
+
+	resp := v1.DialogUnassignResponse{PreviousResponsible: dialog.Responsible}
+	dialog.Responsible = v1.Responsible{}
+	dialog.IsAssigned = false
+	dialog.UpdatedAt = now()
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) dialogClose(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	dialog := s.dialog(parseID(params[0]))
+	if dialog == nil {
+		s.mu.Unlock()
+		writeErrors(w, http.StatusNotFound, "Dialog not found")
+		return
+	}
+
+	if !dialog.IsActive {
+		s.mu.Unlock()
+		writeErrors(w, http.StatusBadRequest, "Dialog is already closed")
+		return
+	}
+
+	closedAt := now()
+	dialog.IsActive = false
+	dialog.ClosedAt = closedAt
+	dialog.UpdatedAt = closedAt
+	data := v1.WsEventDialogClosedData{Dialog: &v1.Dialog{
+		ID:        dialog.ID,
+		Chat:      &v1.Chat{ID: dialog.ChatID},
+		CreatedAt: dialog.CreatedAt,
+		ClosedAt:  &closedAt,
+	}}
+	s.mu.Unlock()
+
+	s.Emit(v1.WsEventDialogClosed, data)
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) dialogTagsAdd(w http.ResponseWriter, r *http.Request, params []string) {
+	var req v1.DialogTagsAddRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := parseID(params[0])
+	if s.dialog(id) == nil {
+		writeErrors(w, http.StatusNotFound, "Dialog not found")
+		return
+	}
+
+	for _, tag := range req.Tags {
+		if indexOf(s.dialogTags[id], tag.Name) < 0 {
+			s.dialogTags[id] = append(s.dialogTags[id], tag.Name)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) dialogTagsDelete(w http.ResponseWriter, r *http.Request, params []string) {
+	var req v1.DialogTagsDeleteRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := parseID(params[0])
+	if s.dialog(id) == nil {
+		writeErrors(w, http.StatusNotFound, "Dialog not found")
+		return
+	}
+
+	for _, tag := range req.Tags {
+		if i := indexOf(s.dialogTags[id], tag.Name); i >= 0 {
+			s.dialogTags[id] = append(s.dialogTags[id][:i], s.dialogTags[id][i+1:]...)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) listMessages(w http.ResponseWriter, r *http.Request, _ []string) {
+	q, ok := newListQuery(w, r)
+	if !ok {
+		return
+	}
+	result := []v1.MessagesResponseItem{}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, message := range s.messages {
+		if q.full(len(result)) {
+			break
+		}
+
+		var dialogID uint64
+		if message.Dialog != nil {
+			dialogID = message.Dialog.ID
+		}
+
+		if q.match(message.ID, message.CreatedAt) && q.equalID("chat_id", message.ChatID) && q.equalID("dialog_id", dialogID) &&
+			q.equalID("channel_id", message.ChannelID) && q.equal("type", message.Type) &&
+			q.equal("scope", message.Scope) && q.sender("bot_id", "bot", message.From) &&
+			q.sender("user_id", "user", message.From) && q.sender("customer_id", "customer", message.From) {
+			result = append(result, message)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) sendMessage(w http.ResponseWriter, r *http.Request, _ []string) {
+	var req v1.MessageSendRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	message, status, errs := s.buildMessage(req)
+	if len(errs) > 0 {
+		s.mu.Unlock()
+		writeErrors(w, status, errs...)
+		return
+	}
+
+	message = s.storeMessage(message)
+	s.mu.Unlock()
+
+	s.Emit(v1.WsEventMessageNew, v1.WsEventMessageNewData{Message: &message.Message})
+	writeJSON(w, http.StatusOK, v1.MessageSendResponse{MessageID: message.ID, Time: message.Time})
+}
+
+// buildMessage validates the request and converts it to the message sent by the bot.
+func (s *Server) buildMessage(req v1.MessageSendRequest) (v1.MessagesResponseItem, int, []string) {
+	bot := s.bot()
+	message := v1.MessagesResponseItem{Message: v1.Message{
+		Type:    req.Type,
+		Scope:   req.Scope,
+		ChatID:  req.ChatID,
+		Status:  "sent",
+		From:    &v1.UserRef{ID: bot.ID, Type: senderBot, Name: bot.Name, Avatar: bot.AvatarUrl},
+		Product: req.Product,
+		Order:   req.Order,
+	}}
+
+	if s.chat(req.ChatID) == nil {
+		return message, http.StatusNotFound, []string{"Chat not found"}
+	}
+
+	var errs []string
+	if req.Scope != v1.MessageScopePublic && req.Scope != v1.MessageScopePrivate {
+		errs = append(errs, "scope: invalid value")
+	}
+
+	switch req.Type {
+	case v1.MsgTypeText, v1.MsgTypeCommand:
+		if req.Content == "" {
+			errs = append(errs, "content: must not be empty")
+		}
+		message.TextMessage = &v1.TextMessage{Content: req.Content}
+	case v1.MsgTypeFile, v1.MsgTypeImage:
+		var attachmentErrs []string
+		message.AttachmentList, attachmentErrs = s.attachments(req)
+		errs = append(errs, attachmentErrs...)
+	case v1.MsgTypeProduct:
+		if req.Product == nil {
+			errs = append(errs, "product: must not be empty")
+		}
+	case v1.MsgTypeOrder:
+		if req.Order == nil {
+			errs = append(errs, "order: must not be empty")
+		}
+	default:
+		errs = append(errs, "type: invalid value")
+	}
+
+	if req.QuoteMessageId != 0 {
+		quote := s.message(req.QuoteMessageId)
+		if quote == nil || quote.ChatID != req.ChatID {
+			errs = append(errs, "quote_message_id: message not found")
+		} else if message.TextMessage != nil {
+			message.Quote = &v1.QuoteMessage{ID: quote.ID, Time: quote.Time, From: quote.From}
+			if quote.TextMessage != nil {
+				message.Quote.Content = quote.Content
+			}
+		}
+	}
+
+	return message, http.StatusBadRequest, errs
+}
+
+func (s *Server) attachments(req v1.MessageSendRequest) (*v1.AttachmentList, []string) {
+	if len(req.Items) == 0 {
+		return nil, []string{"items: must not be empty"}
+	}
+
+	var errs []string
+	list := &v1.AttachmentList{Note: req.Content}
+	for _, item := range req.Items {
+		file, ok := s.files[item.ID]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("items: file %s not found", item.ID))
+			continue
+		}
+
+		list.Items = append(list.Items, v1.Attachment{
+			File:    v1.File{ID: file.ID, Mime: file.MimeType, Type: file.Type, Size: uint64(file.Size)},
+			Caption: item.Caption,
+		})
+	}
+
+	return list, errs
+}
+
+func (s *Server) editMessage(w http.ResponseWriter, r *http.Request, params []string) {
+	var req v1.MessageEditRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	message := s.message(parseID(params[0]))
+	if message == nil {
+		s.mu.Unlock()
+		writeErrors(w, http.StatusNotFound, "Message not found")
+		return
+	}
+
+	if message.TextMessage == nil || message.From == nil || message.From.Type != senderBot {
+		s.mu.Unlock()
+		writeErrors(w, http.StatusBadRequest, "Message can not be edited")
+		return
+	}
+
+	text := *message.TextMessage
+	text.Content = req.Content
+	message.TextMessage = &text
+	message.IsEdit = true
+	message.UpdatedAt = now()
+	edited := message.Message
+	s.mu.Unlock()
+
+	s.Emit(v1.WsEventMessageUpdated, v1.WsEventMessageUpdatedData{Message: &edited})
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) deleteMessage(w http.ResponseWriter, r *http.Request, params []string) {
+	id := parseID(params[0])
+
+	s.mu.Lock()
+	message := s.message(id)
+	if message == nil {
+		s.mu.Unlock()
+		writeErrors(w, http.StatusNotFound, "Message not found")
+		return
+	}
+
+	deleted := v1.Message{ID: message.ID, ChatID: message.ChatID, Type: message.Type}
+	for i := range s.messages {
+		if s.messages[i].ID == id {
+			s.messages = append(s.messages[:i], s.messages[i+1:]...)
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	s.Emit(v1.WsEventMessageDeleted, v1.WsEventMessageDeletedData{Message: &deleted})
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) updateInfo(w http.ResponseWriter, r *http.Request, _ []string) {
+	var req v1.InfoRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bot := s.bot()
+	if req.Name != "" {
+		bot.Name = req.Name
+	}
+
+	if req.Avatar != "" {
+		bot.AvatarUrl = req.Avatar
+	}
+
+	if req.Roles != nil {
+		bot.Roles = req.Roles
+	}
+
+	bot.UpdatedAt = now()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{})
+}
+
+func (s *Server) listCommands(w http.ResponseWriter, r *http.Request, _ []string) {
+	q, ok := newListQuery(w, r)
+	if !ok {
+		return
+	}
+	result := []v1.CommandsResponseItem{}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, command := range s.commands {
+		if q.full(len(result)) {
+			break
+		}
+
+		if q.match(command.ID, command.CreatedAt) && q.equal("name", command.Name) {
+			result = append(result, command)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) editCommand(w http.ResponseWriter, r *http.Request, params []string) {
+	var req v1.CommandEditRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if req.Description == "" {
+		writeErrors(w, http.StatusBadRequest, "description: must not be empty")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := params[0]
+	for i := range s.commands {
+		if s.commands[i].Name == name {
+			s.commands[i].Description = req.Description
+			s.commands[i].UpdatedAt = now()
+			writeJSON(w, http.StatusOK, s.commands[i])
+			return
+		}
+	}
+
+	command := v1.CommandsResponseItem{ID: s.id(0), Name: name, Description: req.Description, CreatedAt: now()}
+	s.commands = append(s.commands, command)
+
+	writeJSON(w, http.StatusOK, command)
+}
+
+func (s *Server) deleteCommand(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.commands {
+		if s.commands[i].Name == params[0] {
+			s.commands = append(s.commands[:i], s.commands[i+1:]...)
+			writeJSON(w, http.StatusOK, map[string]interface{}{})
+			return
+		}
+	}
+
+	writeErrors(w, http.StatusNotFound, "Command not found")
+}
+
+func (s *Server) uploadFile(w http.ResponseWriter, r *http.Request, _ []string) {
+	data, mimeType, err := readUpload(r)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(data) == 0 {
+		writeErrors(w, http.StatusBadRequest, "File is empty")
+		return
+	}
+
+	if mimeType == "" || mimeType == "application/json" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, s.storeFile(data, mimeType))
+}
+
+// readUpload returns the uploaded file and its MIME type. The file is either the raw request body
+// or the first file part of the multipart form.
+func readUpload(r *http.Request) ([]byte, string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		data, err := ioutil.ReadAll(r.Body)
+		return data, r.Header.Get("Content-Type"), err
+	}
+
+	form, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", err
+	}
+
+	for {
+		part, err := form.NextPart()
+		if err != nil {
+			return nil, "", errors.New("file part is missing")
+		}
+
+		if part.FileName() == "" {
+			continue
+		}
+
+		data, err := ioutil.ReadAll(part)
+		return data, part.Header.Get("Content-Type"), err
+	}
+}
+
+func (s *Server) uploadFileByURL(w http.ResponseWriter, r *http.Request, _ []string) {
+	var req v1.UploadFileByUrlRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	data, mimeType, err := s.fetch(req.Url)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, "Can not download file")
+		return
+	}
+
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file := s.storeFile(data, mimeType)
+	file.Url = &req.Url
+	s.files[file.ID].Url = &req.Url
+
+	writeJSON(w, http.StatusOK, file)
+}
+
+func (s *Server) getFile(w http.ResponseWriter, r *http.Request, params []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, ok := s.files[params[0]]
+	if !ok {
+		writeErrors(w, http.StatusNotFound, "File not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, v1.FullFileResponse{
+		ID:   file.ID,
+		Type: file.Type,
+		Size: file.Size,
+		Url:  s.URL + fileDataPath + file.ID,
+	})
+}
+
+func (s *Server) updateFileMeta(w http.ResponseWriter, r *http.Request, params []string) {
+	var req v1.UpdateFileMetadataRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, ok := s.files[params[0]]
+	if !ok {
+		writeErrors(w, http.StatusNotFound, "File not found")
+		return
+	}
+
+	if req.Transcription != "" {
+		file.Transcription = req.Transcription
+	}
+
+	if req.TranscriptionStatus != "" {
+		file.TranscriptionStatus = req.TranscriptionStatus
+	}
+
+	writeJSON(w, http.StatusOK, file.UploadFileResponse)
+}
+
+// fileData serves file contents by URL returned from GetFile.
+func (s *Server) fileData(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	file, ok := s.files[strings.TrimPrefix(r.URL.Path, fileDataPath)]
+	s.mu.Unlock()
+
+	if !ok || r.Method != http.MethodGet {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", file.MimeType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(file.Data)))
+	_, _ = w.Write(file.Data)
+}
+
+// listQuery implements common filters and since_id pagination of list endpoints.
+// Since and until filter items by creation time: since is inclusive, until is exclusive.
+type listQuery struct {
+	values  url.Values
+	ids     map[uint64]bool
+	sinceID uint64
+	untilID uint64
+	since   time.Time
+	until   time.Time
+	limit   int
+}
+
+// newListQuery parses the request query. It writes the error response and returns false for invalid time filters.
+func newListQuery(w http.ResponseWriter, r *http.Request) (listQuery, bool) {
+	q := listQuery{values: r.URL.Query(), ids: map[uint64]bool{}, limit: defaultLimit}
+	for _, id := range q.values["id"] {
+		q.ids[parseID(id)] = true
+	}
+
+	q.sinceID = q.id("since_id")
+	q.untilID = q.id("until_id")
+	if limit := q.id("limit"); limit > 0 {
+		q.limit = int(limit)
+	}
+
+	var errs []string
+	for name, value := range map[string]*time.Time{"since": &q.since, "until": &q.until} {
+		parsed, err := v1.ParseTimestamp(q.values.Get(name))
+		if err != nil {
+			errs = append(errs, name+": invalid value")
+		}
+
+		*value = parsed.Time
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		writeErrors(w, http.StatusBadRequest, errs...)
+		return q, false
+	}
+
+	return q, true
+}
+
+func (q listQuery) id(name string) uint64 {
+	return parseID(q.values.Get(name))
+}
+
+func (q listQuery) full(n int) bool {
+	return n >= q.limit
+}
+
+func (q listQuery) match(id uint64, createdAt string) bool {
+	if (len(q.ids) > 0 && !q.ids[id]) || (q.sinceID > 0 && id <= q.sinceID) || (q.untilID > 0 && id >= q.untilID) {
+		return false
+	}
+
+	if q.since.IsZero() && q.until.IsZero() {
+		return true
+	}
+
+	created, err := v1.ParseTimestamp(createdAt)
+	if err != nil || created.IsZero() {
+		return false
+	}
+
+	return !created.Before(q.since) && (q.until.IsZero() || created.Before(q.until))
+}
+
+func (q listQuery) equal(name, value string) bool {
+	expected := q.values.Get(name)
+	return expected == "" || expected == value
+}
+
+func (q listQuery) equalID(name string, value uint64) bool {
+	expected := q.id(name)
+	return expected == 0 || expected == value
+}
+
+func (q listQuery) flag(name string, value bool) bool {
+	return q.values.Get(name) != "1" || value
+}
+
+func (q listQuery) oneOf(name, value string) bool {
+	values := q.values[name]
+	return len(values) == 0 || indexOf(values, value) >= 0
+}
+
+func (q listQuery) contains(name string, values []string) bool {
+	expected := q.values.Get(name)
+	return expected == "" || indexOf(values, expected) >= 0
+}
+
+func (q listQuery) sender(name, senderType string, from *v1.UserRef) bool {
+	expected := q.id(name)
+	return expected == 0 || (from != nil && from.Type == senderType && from.ID == expected)
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	data, err := ioutil.ReadAll(r.Body)
+	if err == nil && len(data) > 0 {
+		err = json.Unmarshal(data, v)
+	}
+
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeErrors(w http.ResponseWriter, status int, errs ...string) {
+	writeJSON(w, status, map[string][]string{"errors": errs})
+}
+
+func parseID(value string) uint64 {
+	id, _ := strconv.ParseUint(value, 10, 64)
+	return id
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func newFileID(id uint64) string {
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", id, id)
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func fileType(mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return "image"
+	case strings.HasPrefix(mimeType, "audio/"):
+		return "audio"
+	default:
+		return "file"
+	}
+}
@@ -0,0 +1,518 @@
+// Package mgtest provides an in-memory MG Bot API server for tests.
+//
+// Server implements /api/bot/v1 endpoints used by v1.MgClient (bots, channels, users, customers, chats,
+// members, dialogs, messages, commands, files and ws) on top of httptest.Server. The state is kept in memory:
+// a message sent through the API appears in Messages results and is delivered as message_new event
+// to the connected websocket listeners.
+//
+// Example:
+//
+//	srv := mgtest.NewServer()
+//	defer srv.Close()
+//
+//	chat := srv.AddChat(v1.ChatResponseItem{Name: "John"})
+//	client := srv.Client()
+//
+//	_, _, err := client.MessageSend(v1.MessageSendRequest{
+//		Type:    v1.MsgTypeText,
+//		Scope:   v1.MessageScopePublic,
+//		ChatID:  chat.ID,
+//		Content: "Hello",
+//	})
+package mgtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+// Token is the bot token accepted by Server.
+const Token = "mgtest_token"
+
+// fetchClient downloads files for upload_by_url requests by default.
+var fetchClient = &http.Client{Timeout: 10 * time.Second}
+
+// File is a file stored by Server.
+type File struct {
+	v1.UploadFileResponse
+	Data                []byte
+	Transcription       string
+	TranscriptionStatus string
+}
+
+// Fetcher downloads the file for upload_by_url requests and returns its contents and MIME type.
+type Fetcher func(url string) (data []byte, mimeType string, err error)
+
+// Server is an in-memory MG Bot API server. All methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	lastID      uint64
+	selfID      uint64
+	bots        []v1.BotsResponseItem
+	channels    []v1.ChannelResponseItem
+	users       []v1.UsersResponseItem
+	customers   []v1.CustomersResponseItem
+	chats       []v1.ChatResponseItem
+	members     []v1.MemberResponseItem
+	dialogs     []v1.DialogResponseItem
+	dialogTags  map[uint64][]string
+	messages    []v1.MessagesResponseItem
+	commands    []v1.CommandsResponseItem
+	files       map[string]*File
+	subscribers map[*subscriber]struct{}
+	fetcher     Fetcher
+}
+
+// NewServer starts a new Server. The server has one active bot which is used as the message sender,
+// see Bot method. The caller should call Close when finished.
+func NewServer() *Server {
+	s := &Server{
+		dialogTags:  map[uint64][]string{},
+		files:       map[string]*File{},
+		subscribers: map[*subscriber]struct{}{},
+	}
+
+	bot := s.AddBot(v1.BotsResponseItem{
+		Name:     "Test bot",
+		ClientID: "mgtest",
+		IsActive: true,
+		IsSelf:   true,
+		Roles:    []string{v1.BotRoleResponsible},
+	})
+	s.selfID = bot.ID
+
+	s.Server = httptest.NewServer(s)
+
+	return s
+}
+
+// Client returns v1.MgClient configured to use the server.
+func (s *Server) Client(opts ...v1.Option) *v1.MgClient {
+	return v1.New(s.URL, Token, opts...)
+}
+
+// Close disconnects websocket listeners and shuts down the server.
+func (s *Server) Close() {
+	s.DisconnectListeners()
+	s.Server.Close()
+}
+
+// DisconnectListeners closes connections of websocket listeners, e.g. to test reconnects.
+// Events emitted until the listeners reconnect are not delivered to them.
+func (s *Server) DisconnectListeners() {
+	s.mu.Lock()
+	subscribers := s.subscribers
+	s.subscribers = map[*subscriber]struct{}{}
+	s.mu.Unlock()
+
+	for sub := range subscribers {
+		sub.close()
+	}
+}
+
+// SetFetcher sets the function downloading files for upload_by_url requests. By default files stored by
+// the server are read from memory and other URLs are downloaded with a 10 second timeout.
+func (s *Server) SetFetcher(fetcher Fetcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fetcher = fetcher
+}
+
+// Bot returns the bot the client is authorized as.
+func (s *Server) Bot() v1.BotsResponseItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return *s.bot()
+}
+
+// AddBot adds the bot. Zero ID and CreatedAt are filled automatically.
+func (s *Server) AddBot(bot v1.BotsResponseItem) v1.BotsResponseItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bot.ID = s.id(bot.ID)
+	bot.CreatedAt = orNow(bot.CreatedAt)
+	s.bots = append(s.bots, bot)
+	sort.Slice(s.bots, func(i, j int) bool { return s.bots[i].ID < s.bots[j].ID })
+
+	return bot
+}
+
+// AddChannel adds the channel. Zero ID and CreatedAt are filled automatically.
+func (s *Server) AddChannel(channel v1.ChannelResponseItem) v1.ChannelResponseItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel.ID = s.id(channel.ID)
+	channel.CreatedAt = orNow(channel.CreatedAt)
+	s.channels = append(s.channels, channel)
+	sort.Slice(s.channels, func(i, j int) bool { return s.channels[i].ID < s.channels[j].ID })
+
+	return channel
+}
+
+// AddUser adds the user. Zero ID and CreatedAt are filled automatically.
+func (s *Server) AddUser(user v1.UsersResponseItem) v1.UsersResponseItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user.ID = s.id(user.ID)
+	user.CreatedAt = orNow(user.CreatedAt)
+	s.users = append(s.users, user)
+	sort.Slice(s.users, func(i, j int) bool { return s.users[i].ID < s.users[j].ID })
+
+	return user
+}
+
+// AddCustomer adds the customer. Zero ID and CreatedAt are filled automatically.
+func (s *Server) AddCustomer(customer v1.CustomersResponseItem) v1.CustomersResponseItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	customer.ID = s.id(customer.ID)
+	customer.CreatedAt = orNow(customer.CreatedAt)
+	s.customers = append(s.customers, customer)
+	sort.Slice(s.customers, func(i, j int) bool { return s.customers[i].ID < s.customers[j].ID })
+
+	return customer
+}
+
+// AddChat adds the chat. Zero ID and CreatedAt are filled automatically.
+func (s *Server) AddChat(chat v1.ChatResponseItem) v1.ChatResponseItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chat.ID = s.id(chat.ID)
+	chat.CreatedAt = orNow(chat.CreatedAt)
+	s.chats = append(s.chats, chat)
+	sort.Slice(s.chats, func(i, j int) bool { return s.chats[i].ID < s.chats[j].ID })
+
+	return chat
+}
+
+// AddMember adds the chat member. Zero ID and CreatedAt are filled automatically.
+func (s *Server) AddMember(member v1.MemberResponseItem) v1.MemberResponseItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	member.ID = s.id(member.ID)
+	member.CreatedAt = orNow(member.CreatedAt)
+	s.members = append(s.members, member)
+	sort.Slice(s.members, func(i, j int) bool { return s.members[i].ID < s.members[j].ID })
+
+	return member
+}
+
+// AddDialog adds the dialog. Zero ID and CreatedAt are filled automatically.
+// Messages sent to the chat are attached to its active dialog.
+func (s *Server) AddDialog(dialog v1.DialogResponseItem) v1.DialogResponseItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dialog.ID = s.id(dialog.ID)
+	dialog.CreatedAt = orNow(dialog.CreatedAt)
+	s.dialogs = append(s.dialogs, dialog)
+	sort.Slice(s.dialogs, func(i, j int) bool { return s.dialogs[i].ID < s.dialogs[j].ID })
+
+	return dialog
+}
+
+// AddCommand adds the bot command. Zero ID and CreatedAt are filled automatically.
+func (s *Server) AddCommand(command v1.CommandsResponseItem) v1.CommandsResponseItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	command.ID = s.id(command.ID)
+	command.CreatedAt = orNow(command.CreatedAt)
+	s.commands = append(s.commands, command)
+	sort.Slice(s.commands, func(i, j int) bool { return s.commands[i].ID < s.commands[j].ID })
+
+	return command
+}
+
+// AddFile stores the file and returns its metadata.
+func (s *Server) AddFile(data []byte, mimeType string) v1.UploadFileResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.storeFile(data, mimeType)
+}
+
+// AddMessage adds the message and emits message_new event. Zero ID, Time and CreatedAt are filled automatically.
+// The message is attached to the active dialog of the chat if Dialog is not set.
+func (s *Server) AddMessage(message v1.MessagesResponseItem) v1.MessagesResponseItem {
+	s.mu.Lock()
+	message = s.storeMessage(message)
+	s.mu.Unlock()
+
+	s.Emit(v1.WsEventMessageNew, v1.WsEventMessageNewData{Message: &message.Message})
+
+	return message
+}
+
+// CustomerMessage adds text message from the customer of the chat and emits message_new event.
+func (s *Server) CustomerMessage(chatID uint64, content string) v1.MessagesResponseItem {
+	s.mu.Lock()
+	var from *v1.UserRef
+	if chat := s.chat(chatID); chat != nil {
+		customer := chat.Customer
+		customer.Type = "customer"
+		from = &customer
+	}
+	s.mu.Unlock()
+
+	return s.AddMessage(v1.MessagesResponseItem{Message: v1.Message{
+		Type:        v1.MsgTypeText,
+		Scope:       v1.MessageScopePublic,
+		ChatID:      chatID,
+		Status:      "received",
+		From:        from,
+		TextMessage: &v1.TextMessage{Content: content},
+	}})
+}
+
+// Messages returns all messages.
+func (s *Server) Messages() []v1.MessagesResponseItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]v1.MessagesResponseItem(nil), s.messages...)
+}
+
+// Dialogs returns all dialogs.
+func (s *Server) Dialogs() []v1.DialogResponseItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]v1.DialogResponseItem(nil), s.dialogs...)
+}
+
+// DialogTags returns names of the dialog tags.
+func (s *Server) DialogTags(dialogID uint64) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.dialogTags[dialogID]...)
+}
+
+// Commands returns all bot commands.
+func (s *Server) Commands() []v1.CommandsResponseItem {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]v1.CommandsResponseItem(nil), s.commands...)
+}
+
+// File returns the stored file.
+func (s *Server) File(id string) (File, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, ok := s.files[id]
+	if !ok {
+		return File{}, false
+	}
+
+	return *file, true
+}
+
+// Emit sends the event to websocket listeners subscribed to the event type.
+// Data is marshaled to JSON, it is usually one of v1.WsEvent*Data types.
+func (s *Server) Emit(eventType string, data interface{}) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		panic(err)
+	}
+
+	event, _ := json.Marshal(v1.WsEvent{
+		Type: eventType,
+		Meta: v1.EventMeta{Timestamp: time.Now().Unix()},
+		Data: raw,
+	})
+
+	s.mu.Lock()
+	var subscribers []*subscriber
+	for sub := range s.subscribers {
+		if sub.events[eventType] {
+			subscribers = append(subscribers, sub)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, sub := range subscribers {
+		if err := sub.send(event); err != nil {
+			s.unsubscribe(sub)
+		}
+	}
+}
+
+func (s *Server) id(id uint64) uint64 {
+	if id == 0 {
+		s.lastID++
+		return s.lastID
+	}
+
+	if id > s.lastID {
+		s.lastID = id
+	}
+
+	return id
+}
+
+func (s *Server) bot() *v1.BotsResponseItem {
+	for i := range s.bots {
+		if s.bots[i].ID == s.selfID {
+			return &s.bots[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) chat(id uint64) *v1.ChatResponseItem {
+	for i := range s.chats {
+		if s.chats[i].ID == id {
+			return &s.chats[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) dialog(id uint64) *v1.DialogResponseItem {
+	for i := range s.dialogs {
+		if s.dialogs[i].ID == id {
+			return &s.dialogs[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) activeDialog(chatID uint64) *v1.DialogResponseItem {
+	for i := len(s.dialogs) - 1; i >= 0; i-- {
+		if s.dialogs[i].ChatID == chatID && s.dialogs[i].IsActive {
+			return &s.dialogs[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) message(id uint64) *v1.MessagesResponseItem {
+	for i := range s.messages {
+		if s.messages[i].ID == id {
+			return &s.messages[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) storeMessage(message v1.MessagesResponseItem) v1.MessagesResponseItem {
+	message.ID = s.id(message.ID)
+	message.Time = orNow(message.Time)
+	message.CreatedAt = orNow(message.CreatedAt)
+
+	if chat := s.chat(message.ChatID); chat != nil {
+		if message.ChannelID == 0 {
+			message.ChannelID = chat.Channel.ID
+		}
+
+		if message.Chat == nil {
+			channel := chat.Channel
+			customer := chat.Customer
+			message.Chat = &v1.Chat{
+				ID:       chat.ID,
+				Name:     chat.Name,
+				Channel:  &channel,
+				Customer: &customer,
+			}
+		}
+
+		chat.LastMessage = message.Message
+		chat.LastActivity = message.Time
+	}
+
+	if message.Dialog == nil {
+		if dialog := s.activeDialog(message.ChatID); dialog != nil {
+			message.Dialog = &v1.MessageDialog{ID: dialog.ID}
+		}
+	}
+
+	s.messages = append(s.messages, message)
+	sort.Slice(s.messages, func(i, j int) bool { return s.messages[i].ID < s.messages[j].ID })
+
+	return message
+}
+
+// fetch downloads the file by URL using the fetcher set by SetFetcher or the default one.
+func (s *Server) fetch(url string) ([]byte, string, error) {
+	s.mu.Lock()
+	fetcher := s.fetcher
+	var file *File
+	if id := strings.TrimPrefix(url, s.URL+fileDataPath); id != url {
+		file = s.files[id]
+	}
+	s.mu.Unlock()
+
+	if fetcher != nil {
+		return fetcher(url)
+	}
+
+	if file != nil {
+		return file.Data, file.MimeType, nil
+	}
+
+	resp, err := fetchClient.Get(url)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+
+	return data, resp.Header.Get("Content-Type"), err
+}
+
+func (s *Server) storeFile(data []byte, mimeType string) v1.UploadFileResponse {
+	file := &File{Data: data}
+	file.ID = newFileID(s.id(0))
+	file.Hash = hash(data)
+	file.Type = fileType(mimeType)
+	file.MimeType = mimeType
+	file.Size = len(data)
+	file.CreatedAt = time.Now().UTC()
+	s.files[file.ID] = file
+
+	return file.UploadFileResponse
+}
+
+func orNow(value string) string {
+	if value != "" {
+		return value
+	}
+
+	return now()
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
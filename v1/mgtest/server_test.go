@@ -0,0 +1,335 @@
+package mgtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+func TestServer_MessageSend(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	channel := srv.AddChannel(v1.ChannelResponseItem{Type: v1.ChannelTypeTelegram, Name: "Shop", IsActive: true})
+	chat := srv.AddChat(v1.ChatResponseItem{
+		Name:     "John",
+		Channel:  v1.Channel{ID: channel.ID, Type: channel.Type},
+		Customer: v1.UserRef{ID: 100, Name: "John"},
+	})
+	dialog := srv.AddDialog(v1.DialogResponseItem{ChatID: chat.ID, IsActive: true})
+
+	client := srv.Client()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make(chan *v1.WsEventMessageNewData, 2)
+	dispatcher := v1.NewWsDispatcher()
+	dispatcher.OnMessageNew(func(ctx context.Context, data *v1.WsEventMessageNewData) error {
+		events <- data
+		return nil
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- client.Listen(ctx, dispatcher.Events(), dispatcher.Handle) }()
+	require.Eventually(t, func() bool { return srv.Listeners() == 1 }, time.Second, 5*time.Millisecond)
+
+	incoming := srv.CustomerMessage(chat.ID, "Where is my order?")
+	resp, status, err := client.MessageSend(v1.MessageSendRequest{
+		Type:           v1.MsgTypeText,
+		Scope:          v1.MessageScopePublic,
+		ChatID:         chat.ID,
+		Content:        "On its way",
+		QuoteMessageId: incoming.ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+
+	for _, content := range []string{"Where is my order?", "On its way"} {
+		select {
+		case data := <-events:
+			assert.Equal(t, content, data.Message.Content)
+			assert.Equal(t, chat.ID, data.Message.Chat.ID)
+		case <-time.After(time.Second):
+			t.Fatal("message_new event was not received")
+		}
+	}
+
+	messages, _, err := client.Messages(v1.MessagesRequest{ChatID: chat.ID, DialogID: dialog.ID})
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, resp.MessageID, messages[1].ID)
+	assert.Equal(t, channel.ID, messages[1].ChannelID)
+	assert.Equal(t, srv.Bot().ID, messages[1].From.ID)
+	assert.Equal(t, "Where is my order?", messages[1].Quote.Content)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestServer_MessageSendErrors(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	_, status, err := client.MessageSend(v1.MessageSendRequest{
+		Type: v1.MsgTypeText, Scope: v1.MessageScopePublic, ChatID: 1000, Content: "Hello",
+	})
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.True(t, v1.IsNotFound(err))
+
+	chat := srv.AddChat(v1.ChatResponseItem{})
+	_, status, err = client.MessageSend(v1.MessageSendRequest{
+		Type: v1.MsgTypeImage, Scope: v1.MessageScopePublic, ChatID: chat.ID, Items: []v1.Item{{ID: "unknown"}},
+	})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.True(t, v1.IsValidation(err))
+
+	_, status, err = v1.New(srv.URL, "wrong").Bots(v1.BotsRequest{})
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.True(t, v1.IsUnauthorized(err))
+}
+
+func TestServer_MessageEditDelete(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	chat := srv.AddChat(v1.ChatResponseItem{})
+	client := srv.Client()
+
+	resp, _, err := client.MessageSend(v1.MessageSendRequest{
+		Type: v1.MsgTypeText, Scope: v1.MessageScopePublic, ChatID: chat.ID, Content: "Hello",
+	})
+	require.NoError(t, err)
+
+	_, _, err = client.MessageEdit(v1.MessageEditRequest{ID: resp.MessageID, Content: "Hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", srv.Messages()[0].Content)
+	assert.True(t, srv.Messages()[0].IsEdit)
+
+	_, _, err = client.MessageDelete(resp.MessageID)
+	require.NoError(t, err)
+	assert.Empty(t, srv.Messages())
+
+	_, status, _ := client.MessageDelete(resp.MessageID)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestServer_Dialogs(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	chat := srv.AddChat(v1.ChatResponseItem{})
+	dialog := srv.AddDialog(v1.DialogResponseItem{ChatID: chat.ID, IsActive: true})
+	client := srv.Client()
+
+	assign, _, err := client.DialogAssign(v1.DialogAssignRequest{DialogID: dialog.ID, UserID: 5})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), assign.Responsible.ID)
+	assert.False(t, assign.IsReAssign)
+
+	_, err = client.DialogsTagsAdd(v1.DialogTagsAddRequest{DialogID: dialog.ID, Tags: []v1.TagsAdd{{Name: "vip"}}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vip"}, srv.DialogTags(dialog.ID))
+
+	_, _, err = client.DialogClose(dialog.ID)
+	require.NoError(t, err)
+
+	dialogs, _, err := client.Dialogs(v1.DialogsRequest{Active: 1})
+	require.NoError(t, err)
+	assert.Empty(t, dialogs)
+	assert.NotEmpty(t, srv.Dialogs()[0].ClosedAt)
+
+	_, status, err := client.DialogClose(dialog.ID)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Error(t, err)
+}
+
+func TestServer_Pagination(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	for i := 0; i < 5; i++ {
+		srv.AddChannel(v1.ChannelResponseItem{Type: v1.ChannelTypeTelegram})
+	}
+	srv.AddChannel(v1.ChannelResponseItem{Type: v1.ChannelTypeWhatsapp})
+
+	it := srv.Client().IterateChannels(context.Background(), v1.ChannelsRequest{
+		Types: []string{v1.ChannelTypeTelegram},
+		Limit: 2,
+	})
+
+	count := 0
+	for it.Next() {
+		assert.Equal(t, v1.ChannelTypeTelegram, it.Item().Type)
+		count++
+	}
+
+	require.NoError(t, it.Err())
+	assert.Equal(t, 5, count)
+}
+
+func TestServer_Commands(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.AddCommand(v1.CommandsResponseItem{Name: "stale", Description: "Stale"})
+
+	router := v1.NewCommandRouter()
+	router.Handle("start", "Start", func(ctx context.Context, call *v1.CommandCall) error { return nil })
+	require.NoError(t, router.Sync(context.Background(), srv.Client()))
+
+	commands := srv.Commands()
+	require.Len(t, commands, 1)
+	assert.Equal(t, "start", commands[0].Name)
+	assert.Equal(t, "Start", commands[0].Description)
+}
+
+func TestServer_Files(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	client := srv.Client()
+	data := []byte("%PDF-1.4 test")
+
+	uploaded, _, err := client.UploadFile(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, len(data), uploaded.Size)
+	assert.Equal(t, "file", uploaded.Type)
+
+	file, _, err := client.GetFile(uploaded.ID)
+	require.NoError(t, err)
+
+	resp, err := http.Get(file.Url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	downloaded, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, data, downloaded)
+
+	var buf bytes.Buffer
+	_, err = client.DownloadFile(context.Background(), uploaded.ID, &buf, v1.DownloadHash(uploaded.Hash))
+	require.NoError(t, err)
+	assert.Equal(t, data, buf.Bytes())
+
+	_, _, err = client.UpdateFileMetadata(v1.UpdateFileMetadataRequest{
+		ID:                  uploaded.ID,
+		TranscriptionStatus: "ready",
+		Transcription:       "text",
+	})
+	require.NoError(t, err)
+
+	stored, ok := srv.File(uploaded.ID)
+	require.True(t, ok)
+	assert.Equal(t, "ready", stored.TranscriptionStatus)
+	assert.Equal(t, "text", stored.Transcription)
+
+	byURL, _, err := client.UploadFileByURL(v1.UploadFileByUrlRequest{Url: file.Url})
+	require.NoError(t, err)
+	assert.Equal(t, uploaded.Hash, byURL.Hash)
+	assert.Equal(t, file.Url, *byURL.Url)
+}
+
+func TestServer_TimeFilters(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	chat := srv.AddChat(v1.ChatResponseItem{})
+	for _, createdAt := range []string{"2021-03-04T10:00:00Z", "2021-03-05T10:00:00Z", "2021-03-06T10:00:00Z"} {
+		srv.AddMessage(v1.MessagesResponseItem{
+			Message:   v1.Message{ChatID: chat.ID, Type: v1.MsgTypeText, TextMessage: &v1.TextMessage{Content: createdAt}},
+			CreatedAt: createdAt,
+		})
+	}
+
+	messages, _, err := srv.Client().Messages(v1.MessagesRequest{
+		Since: time.Date(2021, 3, 5, 10, 0, 0, 0, time.UTC),
+		Until: time.Date(2021, 3, 6, 10, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "2021-03-05T10:00:00Z", messages[0].CreatedAt)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/bot/v1/messages?since=yesterday", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Bot-Token", Token)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestServer_RecoverySince(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	chat := srv.AddChat(v1.ChatResponseItem{})
+	srv.AddMessage(v1.MessagesResponseItem{
+		Message:   v1.Message{ChatID: chat.ID, Type: v1.MsgTypeText, TextMessage: &v1.TextMessage{Content: "old"}},
+		CreatedAt: time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+	})
+
+	events := make(chan v1.WsEvent, 10)
+	handler := func(ctx context.Context, event v1.WsEvent) error {
+		events <- event
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	policy := v1.ReconnectPolicy{InitialBackoff: 200 * time.Millisecond, MaxBackoff: 200 * time.Millisecond}
+	go func() {
+		_ = srv.Client().Listen(ctx, []string{v1.WsEventMessageNew}, handler, v1.ListenReconnect(policy))
+	}()
+	require.Eventually(t, func() bool { return srv.Listeners() == 1 }, time.Second, 5*time.Millisecond)
+
+	// The message is sent while the listener is disconnected, so it is delivered by recovery only.
+	srv.DisconnectListeners()
+	missed := srv.CustomerMessage(chat.ID, "missed")
+
+	select {
+	case event := <-events:
+		assert.True(t, event.Recovered)
+
+		data, err := v1.DecodeWsEvent(event)
+		require.NoError(t, err)
+		assert.Equal(t, missed.ID, data.(*v1.WsEventMessageNewData).Message.ID)
+	case <-time.After(2 * time.Second):
+		t.Fatal("missed message was not recovered")
+	}
+
+	require.Eventually(t, func() bool { return srv.Listeners() == 1 }, time.Second, 5*time.Millisecond)
+	assert.Empty(t, events, "messages created before Listen must not be recovered")
+}
+
+func TestServer_UploadFileByURLFetcher(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	srv.SetFetcher(func(url string) ([]byte, string, error) {
+		if url != "https://example.com/photo" {
+			return nil, "", errors.New("not found")
+		}
+
+		return []byte("photo"), "image/png", nil
+	})
+
+	uploaded, _, err := srv.Client().UploadFileByURL(v1.UploadFileByUrlRequest{Url: "https://example.com/photo"})
+	require.NoError(t, err)
+	assert.Equal(t, "image", uploaded.Type)
+
+	file, ok := srv.File(uploaded.ID)
+	require.True(t, ok)
+	assert.Equal(t, []byte("photo"), file.Data)
+}
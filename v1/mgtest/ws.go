@@ -0,0 +1,91 @@
+package mgtest
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const wsWriteWait = 5 * time.Second
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// subscriber is a websocket listener connected to the server.
+type subscriber struct {
+	mu     sync.Mutex
+	conn   *websocket.Conn
+	events map[string]bool
+}
+
+func (sub *subscriber) send(data []byte) error {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	_ = sub.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+
+	return sub.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (sub *subscriber) close() {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	_ = sub.conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseGoingAway, ""),
+		time.Now().Add(wsWriteWait),
+	)
+	_ = sub.conn.Close()
+}
+
+func (s *Server) serveWs(w http.ResponseWriter, r *http.Request, _ []string) {
+	events := r.URL.Query().Get("events")
+	if events == "" {
+		writeErrors(w, http.StatusBadRequest, "events: must not be empty")
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	sub := &subscriber{conn: conn, events: map[string]bool{}}
+	for _, event := range strings.Split(events, ",") {
+		sub.events[event] = true
+	}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	defer s.unsubscribe(sub)
+
+	// The server does not expect messages from listeners, reading is needed to process control frames.
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.mu.Lock()
+	delete(s.subscribers, sub)
+	s.mu.Unlock()
+
+	_ = sub.conn.Close()
+}
+
+// Listeners returns the number of connected websocket listeners.
+func (s *Server) Listeners() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.subscribers)
+}
@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+)
+
+// APIRequest is the API call passed through the middleware chain.
+type APIRequest struct {
+	// Method is the HTTP method, e.g. "POST".
+	Method string
+	// Path is the endpoint path relative to the API prefix, e.g. "/messages".
+	Path string
+	// Body is the JSON request body. It is nil for file uploads. Changing it has no effect.
+	Body []byte
+	// HTTPRequest is the request to send. Middleware may change its headers, e.g. X-Bot-Token.
+	HTTPRequest *http.Request
+}
+
+// Context returns the request context.
+func (r *APIRequest) Context() context.Context {
+	return r.HTTPRequest.Context()
+}
+
+// APIResponse is the API response read by the client.
+type APIResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	// Attempts is the number of attempts made according to the retry policy.
+	Attempts int
+}
+
+// Doer sends API requests. The response is nil if no response was received (e.g. on network errors).
+// For 4xx and 5xx responses both the response and *APIError are returned.
+type Doer interface {
+	Do(req *APIRequest) (*APIResponse, error)
+}
+
+// DoerFunc is an adapter to use ordinary functions as Doer.
+type DoerFunc func(req *APIRequest) (*APIResponse, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *APIRequest) (*APIResponse, error) {
+	return f(req)
+}
+
+// Middleware wraps Doer to run code before and after API calls. It may change the request, the response and
+// the error or return a response without calling next.
+type Middleware func(next Doer) Doer
+
+// OptionMiddleware adds middleware to the client. The first middleware is the outermost one: it is called first
+// and receives the response last. Every call is passed through the chain once, retries happen inside it
+// and are reported in APIResponse.Attempts. File downloads and WebSocket connections do not use middleware.
+//
+// Example:
+//
+//	audit := func(next v1.Doer) v1.Doer {
+//		return v1.DoerFunc(func(req *v1.APIRequest) (*v1.APIResponse, error) {
+//			resp, err := next.Do(req)
+//			if err != nil {
+//				log.Printf("%s %s: %v", req.Method, req.Path, err)
+//			}
+//
+//			return resp, err
+//		})
+//	}
+//
+//	client := v1.New("https://token.url", "cb8ccf05e38a47543ad8477d49bcba99be73bff503ea6",
+//		v1.OptionMiddleware(audit))
+func OptionMiddleware(middleware ...Middleware) func(*MgClient) {
+	return func(c *MgClient) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// doer returns the client Doer wrapped with middleware.
+func (c *MgClient) doer() Doer {
+	var doer Doer = DoerFunc(c.doAPI)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		doer = c.middleware[i](doer)
+	}
+
+	return doer
+}
+
+// doAPI sends the request with retries and reads the response.
+func (c *MgClient) doAPI(req *APIRequest) (*APIResponse, error) {
+	httpResp, attempts, err := c.do(req.HTTPRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := buildRawResponse(httpResp)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &APIResponse{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		Body:       body,
+		Attempts:   attempts,
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return resp, newAPIError(req.Method, req.Path, resp.StatusCode, body)
+	}
+
+	return resp, nil
+}
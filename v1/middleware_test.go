@@ -0,0 +1,185 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// requestRecorder records the API requests sent by the client.
+type requestRecorder struct {
+	mu       sync.Mutex
+	requests []*APIRequest
+}
+
+func (r *requestRecorder) option() Option {
+	return OptionMiddleware(func(next Doer) Doer {
+		return DoerFunc(func(req *APIRequest) (*APIResponse, error) {
+			r.mu.Lock()
+			r.requests = append(r.requests, req)
+			r.mu.Unlock()
+
+			return next.Do(req)
+		})
+	})
+}
+
+func (r *requestRecorder) all() []*APIRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*APIRequest(nil), r.requests...)
+}
+
+func (r *requestRecorder) paths() []string {
+	var paths []string
+	for _, req := range r.all() {
+		paths = append(paths, req.Method+" "+req.Path)
+	}
+
+	return paths
+}
+
+func TestMgClient_Middleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "rotated_token", r.Header.Get("X-Bot-Token"))
+
+		if r.URL.Path == "/api/bot/v1/messages/404" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": ["Message not found"]}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"message_id": 1, "time": "2024-01-31T10:00:00Z"}`))
+	}))
+	defer srv.Close()
+
+	var calls []string
+	tracer := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(req *APIRequest) (*APIResponse, error) {
+				calls = append(calls, name+" "+req.Method+" "+req.Path)
+				resp, err := next.Do(req)
+				calls = append(calls, name+" done")
+
+				return resp, err
+			})
+		}
+	}
+
+	var (
+		body    []byte
+		status  int
+		lastErr error
+	)
+	audit := func(next Doer) Doer {
+		return DoerFunc(func(req *APIRequest) (*APIResponse, error) {
+			req.HTTPRequest.Header.Set("X-Bot-Token", "rotated_token")
+			body = req.Body
+
+			resp, err := next.Do(req)
+			status, lastErr = resp.StatusCode, err
+
+			return resp, err
+		})
+	}
+
+	c := New(srv.URL, mgToken, OptionMiddleware(tracer("first"), tracer("second")), OptionMiddleware(audit))
+
+	resp, code, err := c.MessageSend(MessageSendRequest{
+		Type: MsgTypeText, Scope: MessageScopePublic, ChatID: 10, Content: "Hello",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, uint64(1), resp.MessageID)
+	assert.Contains(t, string(body), `"content":"Hello"`)
+	assert.Equal(t, []string{"first POST /messages", "second POST /messages", "second done", "first done"}, calls)
+	assert.NoError(t, lastErr)
+
+	_, code, err = c.MessageDelete(404)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, http.StatusNotFound, status)
+
+	var apiErr *APIError
+	require.True(t, errors.As(lastErr, &apiErr))
+	assert.Equal(t, "/messages/404", apiErr.Endpoint)
+}
+
+func TestMgClient_MiddlewareFaultInjection(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	unavailable := func(next Doer) Doer {
+		return DoerFunc(func(req *APIRequest) (*APIResponse, error) {
+			if req.Path != "/bots" {
+				return next.Do(req)
+			}
+
+			body := []byte(`{"errors": ["Service unavailable"]}`)
+			return &APIResponse{StatusCode: http.StatusServiceUnavailable, Body: body},
+				newAPIError(req.Method, req.Path, http.StatusServiceUnavailable, body)
+		})
+	}
+
+	broken := func(next Doer) Doer {
+		return DoerFunc(func(req *APIRequest) (*APIResponse, error) {
+			return nil, nil
+		})
+	}
+
+	c := New(srv.URL, mgToken, OptionMiddleware(unavailable))
+
+	_, status, err := c.Bots(BotsRequest{})
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.EqualError(t, err, "Service unavailable")
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	_, _, err = c.Channels(ChannelsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	_, _, err = New(srv.URL, mgToken, OptionMiddleware(broken)).Bots(BotsRequest{})
+	assert.EqualError(t, err, "middleware returned no response")
+}
+
+func TestMgClient_MiddlewareAttempts(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	var attempts int
+	count := func(next Doer) Doer {
+		return DoerFunc(func(req *APIRequest) (*APIResponse, error) {
+			resp, err := next.Do(req)
+			if resp != nil {
+				attempts = resp.Attempts
+			}
+
+			return resp, err
+		})
+	}
+
+	c := New(srv.URL, mgToken, OptionRetryPolicy(retryTestPolicy()), OptionMiddleware(count))
+
+	_, _, err := c.Bots(BotsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
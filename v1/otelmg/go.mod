@@ -0,0 +1,15 @@
+module github.com/retailcrm/mg-bot-api-client-go/v1/otelmg
+
+go 1.16
+
+require (
+	github.com/retailcrm/mg-bot-api-client-go v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel v1.7.0
+	go.opentelemetry.io/otel/sdk v1.7.0
+	go.opentelemetry.io/otel/trace v1.7.0
+	golang.org/x/sys v0.10.0 // indirect
+)
+
+// The module is not published yet: no tagged client release contains v1.OptionMiddleware, so it is built
+// and tested against the client in this repository only. Require that release and drop the replace once it is tagged.
+replace github.com/retailcrm/mg-bot-api-client-go => ../..
@@ -0,0 +1,157 @@
+// Package otelmg traces MgClient API calls and WebSocket events with OpenTelemetry.
+//
+// It is a separate module, so the client does not depend on OpenTelemetry unless tracing is used.
+// The module is not published yet, it is built against the client from the same repository.
+//
+// Example:
+//
+//	client := v1.New("https://token.url", "cb8ccf05e38a47543ad8477d49bcba99be73bff503ea6",
+//		v1.OptionMiddleware(otelmg.Middleware()))
+//
+//	dispatcher := v1.NewWsDispatcher()
+//	err := client.Listen(ctx, dispatcher.Events(), otelmg.WsHandler(dispatcher.Handle))
+package otelmg
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+)
+
+// InstrumentationName is the name of the tracer used by the package.
+const InstrumentationName = "github.com/retailcrm/mg-bot-api-client-go/v1/otelmg"
+
+// Span attributes set in addition to semantic convention ones.
+const (
+	// AttributeEndpoint is the API endpoint path relative to the API prefix, e.g. "/messages/123".
+	AttributeEndpoint = attribute.Key("mg.endpoint")
+	// AttributeRetries is the number of retries made according to the client retry policy.
+	AttributeRetries = attribute.Key("mg.retries")
+	// AttributeEventType is the WebSocket event type, e.g. "message_new".
+	AttributeEventType = attribute.Key("mg.ws.event_type")
+	// AttributeRecovered is set for events restored after reconnecting, see v1.WsEvent.Recovered.
+	AttributeRecovered = attribute.Key("mg.ws.recovered")
+)
+
+type config struct {
+	provider    trace.TracerProvider
+	propagators propagation.TextMapPropagator
+}
+
+// Option configures tracing.
+type Option func(*config)
+
+// OptionTracerProvider sets the tracer provider. The global provider is used by default.
+func OptionTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// OptionPropagators sets propagators used to inject the trace context into API requests.
+// The global propagators are used by default.
+func OptionPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = propagators
+	}
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{
+		provider:    otel.GetTracerProvider(),
+		propagators: otel.GetTextMapPropagator(),
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return cfg
+}
+
+// Middleware returns v1.Middleware which creates a client span for every API call. The span is named after
+// the method and the endpoint with IDs replaced by placeholders, e.g. "MG PATCH /dialogs/{id}/assign".
+// It records the status code, the number of retries and the error. The trace context is injected into
+// request headers.
+func Middleware(opts ...Option) v1.Middleware {
+	cfg := newConfig(opts)
+	tracer := cfg.provider.Tracer(InstrumentationName)
+
+	return func(next v1.Doer) v1.Doer {
+		return v1.DoerFunc(func(req *v1.APIRequest) (*v1.APIResponse, error) {
+			ctx, span := tracer.Start(req.Context(), "MG "+req.Method+" "+route(req.Path),
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(semconv.HTTPMethodKey.String(req.Method), AttributeEndpoint.String(req.Path)),
+			)
+			defer span.End()
+
+			req.HTTPRequest = req.HTTPRequest.WithContext(ctx)
+			cfg.propagators.Inject(ctx, propagation.HeaderCarrier(req.HTTPRequest.Header))
+
+			resp, err := next.Do(req)
+			if resp != nil {
+				span.SetAttributes(
+					semconv.HTTPStatusCodeKey.Int(resp.StatusCode),
+					AttributeRetries.Int(resp.Attempts-1),
+				)
+			}
+
+			recordError(span, err)
+
+			return resp, err
+		})
+	}
+}
+
+// WsHandler wraps the handler to create a consumer span for every WebSocket event. The handler receives
+// the context with the span, so API calls made by the handler are traced as its children.
+func WsHandler(handler v1.WsEventHandler, opts ...Option) v1.WsEventHandler {
+	cfg := newConfig(opts)
+	tracer := cfg.provider.Tracer(InstrumentationName)
+
+	return func(ctx context.Context, event v1.WsEvent) error {
+		ctx, span := tracer.Start(ctx, "MG WS "+event.Type,
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(AttributeEventType.String(event.Type), AttributeRecovered.Bool(event.Recovered)),
+		)
+		defer span.End()
+
+		err := handler(ctx, event)
+		recordError(span, err)
+
+		return err
+	}
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// route replaces IDs and command names in the endpoint path with placeholders to keep span names generic.
+func route(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case i > 0 && segments[i-1] == "commands":
+			segments[i] = "{name}"
+		case strings.IndexFunc(segment, unicode.IsDigit) >= 0:
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
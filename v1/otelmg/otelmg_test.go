@@ -0,0 +1,141 @@
+package otelmg
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	v1 "github.com/retailcrm/mg-bot-api-client-go/v1"
+	"github.com/retailcrm/mg-bot-api-client-go/v1/mgtest"
+)
+
+func TestMiddleware(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	srv := mgtest.NewServer()
+	defer srv.Close()
+
+	chat := srv.AddChat(v1.ChatResponseItem{})
+	client := srv.Client(v1.OptionMiddleware(Middleware(OptionTracerProvider(provider))))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "parent")
+	resp, _, err := client.MessageSendContext(ctx, v1.MessageSendRequest{
+		Type: v1.MsgTypeText, Scope: v1.MessageScopePublic, ChatID: chat.ID, Content: "Hello",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = client.MessageDeleteContext(ctx, resp.MessageID+100)
+	if !v1.IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	send, remove := spans[0], spans[1]
+	assertSpan(t, send, "MG POST /messages", trace.SpanKindClient, codes.Unset)
+	assertAttributes(t, send, map[string]string{
+		"http.method": "POST", "http.status_code": "200", "mg.endpoint": "/messages", "mg.retries": "0",
+	})
+
+	assertSpan(t, remove, "MG DELETE /messages/{id}", trace.SpanKindClient, codes.Error)
+	assertAttributes(t, remove, map[string]string{"http.status_code": "404"})
+	if len(remove.Events()) != 1 || remove.Events()[0].Name != "exception" {
+		t.Errorf("expected error event, got %v", remove.Events())
+	}
+
+	for _, span := range spans[:2] {
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s is not a child of the parent span", span.Name())
+		}
+	}
+}
+
+func TestWsHandler(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	srv := mgtest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client(v1.OptionMiddleware(Middleware(OptionTracerProvider(provider))))
+	handlerErr := errors.New("handler error")
+
+	handler := WsHandler(func(ctx context.Context, event v1.WsEvent) error {
+		if _, _, err := client.BotsContext(ctx, v1.BotsRequest{Self: 1}); err != nil {
+			return err
+		}
+
+		return handlerErr
+	}, OptionTracerProvider(provider))
+
+	err := handler(context.Background(), v1.WsEvent{Type: v1.WsEventMessageNew, Recovered: true})
+	if !errors.Is(err, handlerErr) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	call, event := spans[0], spans[1]
+	assertSpan(t, event, "MG WS message_new", trace.SpanKindConsumer, codes.Error)
+	assertAttributes(t, event, map[string]string{"mg.ws.event_type": "message_new", "mg.ws.recovered": "true"})
+	assertSpan(t, call, "MG GET /bots", trace.SpanKindClient, codes.Unset)
+
+	if call.Parent().SpanID() != event.SpanContext().SpanID() {
+		t.Error("API call span is not a child of the event span")
+	}
+}
+
+func TestRoute(t *testing.T) {
+	tests := map[string]string{
+		"/messages":                           "/messages",
+		"/dialogs/12/assign":                  "/dialogs/{id}/assign",
+		"/my/commands/start":                  "/my/commands/{name}",
+		"/files/8ab3c1e4-2a3b-4c5d-9e8f/meta": "/files/{id}/meta",
+		"/files/upload_by_url":                "/files/upload_by_url",
+	}
+
+	for path, expected := range tests {
+		if actual := route(path); actual != expected {
+			t.Errorf("route(%q) = %q, expected %q", path, actual, expected)
+		}
+	}
+}
+
+func assertSpan(t *testing.T, span sdktrace.ReadOnlySpan, name string, kind trace.SpanKind, status codes.Code) {
+	t.Helper()
+
+	if span.Name() != name || span.SpanKind() != kind || span.Status().Code != status {
+		t.Errorf("expected span %s (%s, %s), got %s (%s, %s)",
+			name, kind, status, span.Name(), span.SpanKind(), span.Status().Code)
+	}
+}
+
+func assertAttributes(t *testing.T, span sdktrace.ReadOnlySpan, expected map[string]string) {
+	t.Helper()
+
+	actual := map[string]string{}
+	for _, attr := range span.Attributes() {
+		actual[string(attr.Key)] = attr.Value.Emit()
+	}
+
+	for key, value := range expected {
+		if actual[key] != value {
+			t.Errorf("span %s: expected %s=%s, got %q", span.Name(), key, value, actual[key])
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	rateLimitDefaultPenalty = time.Second
+	rateLimitMinFactor      = 1.0 / 16
+	rateLimitRecoveryStep   = 1.05
+)
+
+// ErrRateLimitExceeded is returned when the client-side rate limiter is configured to fail fast
+// and there are no tokens left. It matches ErrRateLimited, so IsRateLimited returns true for it.
+var ErrRateLimitExceeded = fmt.Errorf("%w: client-side limit exceeded", ErrRateLimited)
+
+// RateLimit describes a token bucket: Rate tokens per second are added to the bucket up to Burst tokens.
+// Zero Rate means no limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimiterConfig configures the client-side rate limiter.
+type RateLimiterConfig struct {
+	// Global limits all requests of the client.
+	Global RateLimit
+	// Groups limits requests to endpoints with the given path prefix relative to the API prefix,
+	// e.g. "/messages" or "/files/upload". The longest matching prefix is used.
+	// A request consumes tokens from both the global and the group bucket.
+	Groups map[string]RateLimit
+	// FailFast makes requests fail with ErrRateLimitExceeded instead of waiting for tokens.
+	FailFast bool
+}
+
+// OptionRateLimiter installs the client-side rate limiter. Requests wait for free tokens until the request
+// context is done. When MG responds with 429 Too Many Requests, the corresponding bucket is paused according
+// to Retry-After header and its rate is temporarily lowered, then restored gradually on successful responses.
+func OptionRateLimiter(config RateLimiterConfig) func(*MgClient) {
+	return func(c *MgClient) {
+		c.rateLimiter = newRateLimiter(config)
+	}
+}
+
+type rateLimiter struct {
+	global   *tokenBucket
+	groups   map[string]*tokenBucket
+	prefixes []string
+	failFast bool
+}
+
+func newRateLimiter(config RateLimiterConfig) *rateLimiter {
+	l := &rateLimiter{
+		global:   newTokenBucket(config.Global),
+		groups:   map[string]*tokenBucket{},
+		failFast: config.FailFast,
+	}
+
+	for prefix, limit := range config.Groups {
+		if bucket := newTokenBucket(limit); bucket != nil {
+			l.groups[prefix] = bucket
+			l.prefixes = append(l.prefixes, prefix)
+		}
+	}
+
+	sort.Slice(l.prefixes, func(i, j int) bool {
+		return len(l.prefixes[i]) > len(l.prefixes[j])
+	})
+
+	return l
+}
+
+// buckets returns non-nil buckets for the endpoint: the group bucket (if any) goes first.
+func (l *rateLimiter) buckets(endpoint string) []*tokenBucket {
+	var buckets []*tokenBucket
+	for _, prefix := range l.prefixes {
+		if strings.HasPrefix(endpoint, prefix) {
+			buckets = append(buckets, l.groups[prefix])
+			break
+		}
+	}
+
+	if l.global != nil {
+		buckets = append(buckets, l.global)
+	}
+
+	return buckets
+}
+
+// wait blocks until the request to the endpoint is allowed or the context is done.
+func (l *rateLimiter) wait(ctx context.Context, endpoint string) error {
+	if l == nil {
+		return nil
+	}
+
+	buckets := l.buckets(endpoint)
+	now := time.Now()
+
+	var delay time.Duration
+	for _, bucket := range buckets {
+		if d := bucket.reserve(now); d > delay {
+			delay = d
+		}
+	}
+
+	if delay <= 0 {
+		return nil
+	}
+
+	if l.failFast {
+		cancelReservations(buckets)
+		return ErrRateLimitExceeded
+	}
+
+	if err := sleepContext(ctx, delay); err != nil {
+		cancelReservations(buckets)
+		return err
+	}
+
+	return nil
+}
+
+// observe adapts the limiter to the response status.
+func (l *rateLimiter) observe(endpoint string, resp *http.Response) {
+	if l == nil || resp == nil {
+		return
+	}
+
+	buckets := l.buckets(endpoint)
+	if len(buckets) == 0 {
+		return
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		for _, bucket := range buckets {
+			bucket.recover()
+		}
+
+		return
+	}
+
+	penalty, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		penalty = rateLimitDefaultPenalty
+	}
+
+	buckets[0].penalize(time.Now(), penalty)
+}
+
+func cancelReservations(buckets []*tokenBucket) {
+	for _, bucket := range buckets {
+		bucket.cancel()
+	}
+}
+
+type tokenBucket struct {
+	mu           sync.Mutex
+	rate         float64
+	burst        float64
+	factor       float64
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	if limit.Rate <= 0 {
+		return nil
+	}
+
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   limit.Rate,
+		burst:  burst,
+		factor: 1,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token and returns the delay after which it can be used.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	b.tokens--
+
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / (b.rate * b.factor) * float64(time.Second))
+	}
+
+	if blocked := b.blockedUntil.Sub(now); blocked > delay {
+		delay = blocked
+	}
+
+	return delay
+}
+
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = math.Min(b.tokens+1, b.burst)
+}
+
+// penalize pauses the bucket and halves its rate.
+func (b *tokenBucket) penalize(now time.Time, pause time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	b.factor = math.Max(b.factor/2, rateLimitMinFactor)
+	b.tokens = math.Min(b.tokens, 0)
+
+	if until := now.Add(pause); until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// recover gradually restores the rate lowered by penalize.
+func (b *tokenBucket) recover() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.factor = math.Min(b.factor*rateLimitRecoveryStep, 1)
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate*b.factor)
+		b.last = now
+	}
+}
@@ -0,0 +1,151 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rateLimitTestServer() (*httptest.Server, *int32) {
+	var calls int32
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		_, _ = w.Write([]byte(`[]`))
+	})), &calls
+}
+
+func TestMgClient_RateLimiterFailFast(t *testing.T) {
+	srv, calls := rateLimitTestServer()
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken, OptionRateLimiter(RateLimiterConfig{
+		Global:   RateLimit{Rate: 0.1, Burst: 2},
+		FailFast: true,
+	}))
+
+	_, _, err := c.Bots(BotsRequest{})
+	require.NoError(t, err)
+	_, _, err = c.Channels(ChannelsRequest{})
+	require.NoError(t, err)
+
+	_, status, err := c.Users(UsersRequest{})
+	require.Error(t, err)
+	assert.Equal(t, 0, status)
+	assert.True(t, errors.Is(err, ErrRateLimitExceeded))
+	assert.True(t, IsRateLimited(err))
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestMgClient_RateLimiterGroups(t *testing.T) {
+	srv, calls := rateLimitTestServer()
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken, OptionRateLimiter(RateLimiterConfig{
+		Groups: map[string]RateLimit{
+			"/messages": {Rate: 0.1, Burst: 1},
+		},
+		FailFast: true,
+	}))
+
+	_, _, err := c.Messages(MessagesRequest{})
+	require.NoError(t, err)
+
+	_, _, err = c.Messages(MessagesRequest{})
+	assert.True(t, IsRateLimited(err))
+
+	for i := 0; i < 3; i++ {
+		_, _, err = c.Bots(BotsRequest{})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(4), atomic.LoadInt32(calls))
+}
+
+func TestMgClient_RateLimiterWait(t *testing.T) {
+	srv, calls := rateLimitTestServer()
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken, OptionRateLimiter(RateLimiterConfig{
+		Global: RateLimit{Rate: 50, Burst: 1},
+	}))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, _, err := c.Bots(BotsRequest{})
+		require.NoError(t, err)
+	}
+
+	assert.True(t, time.Since(start) >= 35*time.Millisecond)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestMgClient_RateLimiterContext(t *testing.T) {
+	srv, calls := rateLimitTestServer()
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken, OptionRateLimiter(RateLimiterConfig{
+		Global: RateLimit{Rate: 0.1, Burst: 1},
+	}))
+
+	_, _, err := c.Bots(BotsRequest{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, _, err = c.BotsContext(ctx, BotsRequest{})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestMgClient_RateLimiterAdaptsTo429(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"errors": ["too many requests"]}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken, OptionRateLimiter(RateLimiterConfig{
+		Global:   RateLimit{Rate: 100, Burst: 10},
+		FailFast: true,
+	}))
+
+	_, status, err := c.Bots(BotsRequest{})
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.True(t, IsRateLimited(err))
+
+	_, status, err = c.Bots(BotsRequest{})
+	assert.Equal(t, 0, status)
+	assert.True(t, errors.Is(err, ErrRateLimitExceeded))
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(RateLimit{Rate: 10, Burst: 2})
+	b.last = now
+
+	assert.Equal(t, time.Duration(0), b.reserve(now))
+	assert.Equal(t, time.Duration(0), b.reserve(now))
+	assert.Equal(t, 100*time.Millisecond, b.reserve(now))
+
+	b.cancel()
+	b.penalize(now, time.Second)
+	assert.InDelta(t, 0.5, b.factor, 0.0001)
+	assert.Equal(t, time.Second, b.reserve(now))
+
+	for i := 0; i < 100; i++ {
+		b.recover()
+	}
+	assert.InDelta(t, 1, b.factor, 0.0001)
+
+	assert.Nil(t, newTokenBucket(RateLimit{}))
+}
@@ -2,17 +2,26 @@ package v1
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strings"
 )
 
 var prefix = "/api/bot/v1"
 
 // GetRequest implements GET Request
 func (c *MgClient) GetRequest(url string, parameters []byte) ([]byte, int, error) {
+	return c.GetRequestContext(context.Background(), url, parameters)
+}
+
+// GetRequestContext implements GET Request with the provided context
+func (c *MgClient) GetRequestContext(ctx context.Context, url string, parameters []byte) ([]byte, int, error) {
 	return makeRequest(
+		ctx,
 		"GET",
 		fmt.Sprintf("%s%s%s", c.URL, prefix, url),
 		bytes.NewBuffer(parameters),
@@ -22,7 +31,13 @@ func (c *MgClient) GetRequest(url string, parameters []byte) ([]byte, int, error
 
 // PostRequest implements POST Request
 func (c *MgClient) PostRequest(url string, parameters io.Reader) ([]byte, int, error) {
+	return c.PostRequestContext(context.Background(), url, parameters)
+}
+
+// PostRequestContext implements POST Request with the provided context
+func (c *MgClient) PostRequestContext(ctx context.Context, url string, parameters io.Reader) ([]byte, int, error) {
 	return makeRequest(
+		ctx,
 		"POST",
 		fmt.Sprintf("%s%s%s", c.URL, prefix, url),
 		parameters,
@@ -32,7 +47,13 @@ func (c *MgClient) PostRequest(url string, parameters io.Reader) ([]byte, int, e
 
 // PatchRequest implements PATCH Request
 func (c *MgClient) PatchRequest(url string, parameters []byte) ([]byte, int, error) {
+	return c.PatchRequestContext(context.Background(), url, parameters)
+}
+
+// PatchRequestContext implements PATCH Request with the provided context
+func (c *MgClient) PatchRequestContext(ctx context.Context, url string, parameters []byte) ([]byte, int, error) {
 	return makeRequest(
+		ctx,
 		"PATCH",
 		fmt.Sprintf("%s%s%s", c.URL, prefix, url),
 		bytes.NewBuffer(parameters),
@@ -42,7 +63,13 @@ func (c *MgClient) PatchRequest(url string, parameters []byte) ([]byte, int, err
 
 // PutRequest implements PUT Request
 func (c *MgClient) PutRequest(url string, parameters []byte) ([]byte, int, error) {
+	return c.PutRequestContext(context.Background(), url, parameters)
+}
+
+// PutRequestContext implements PUT Request with the provided context
+func (c *MgClient) PutRequestContext(ctx context.Context, url string, parameters []byte) ([]byte, int, error) {
 	return makeRequest(
+		ctx,
 		"PUT",
 		fmt.Sprintf("%s%s%s", c.URL, prefix, url),
 		bytes.NewBuffer(parameters),
@@ -52,7 +79,13 @@ func (c *MgClient) PutRequest(url string, parameters []byte) ([]byte, int, error
 
 // DeleteRequest implements DELETE Request
 func (c *MgClient) DeleteRequest(url string, parameters []byte) ([]byte, int, error) {
+	return c.DeleteRequestContext(context.Background(), url, parameters)
+}
+
+// DeleteRequestContext implements DELETE Request with the provided context
+func (c *MgClient) DeleteRequestContext(ctx context.Context, url string, parameters []byte) ([]byte, int, error) {
 	return makeRequest(
+		ctx,
 		"DELETE",
 		fmt.Sprintf("%s%s%s", c.URL, prefix, url),
 		bytes.NewBuffer(parameters),
@@ -60,40 +93,102 @@ func (c *MgClient) DeleteRequest(url string, parameters []byte) ([]byte, int, er
 	)
 }
 
-func makeRequest(reqType, url string, buf io.Reader, c *MgClient) ([]byte, int, error) {
-	var res []byte
-	req, err := http.NewRequest(reqType, url, buf)
+func makeRequest(ctx context.Context, reqType, url string, buf io.Reader, c *MgClient) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, reqType, url, buf)
 	if err != nil {
-		return res, 0, err
+		return nil, 0, err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+
+	return c.sendRequest(req, buf)
+}
+
+// sendRequest sends the API request through the middleware chain and reads the response.
+// The body is passed to middleware and used for debug logging.
+func (c *MgClient) sendRequest(req *http.Request, body io.Reader) ([]byte, int, error) {
 	req.Header.Set("X-Bot-Token", c.Token)
 
 	if c.Debug {
-		c.writeLog("MG BOT API Request: %s %s %s %+v", reqType, url, c.Token, buf)
+		c.writeLog("MG BOT API Request: %s %s %s %+v", req.Method, req.URL, c.Token, debugBody(body))
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return res, 0, err
+	apiReq := &APIRequest{Method: req.Method, Path: endpointPath(req.URL.Path), HTTPRequest: req}
+	if buf, ok := body.(*bytes.Buffer); ok {
+		apiReq.Body = buf.Bytes()
 	}
 
-	if resp.StatusCode >= http.StatusInternalServerError {
-		err = fmt.Errorf("http request error. Status code: %d", resp.StatusCode)
-		return res, resp.StatusCode, err
+	resp, err := c.doer().Do(apiReq)
+	if resp == nil {
+		if err == nil {
+			err = errors.New("middleware returned no response")
+		}
+
+		return nil, 0, err
 	}
 
-	res, err = buildRawResponse(resp)
-	if err != nil {
-		return res, 0, err
+	// Methods check statuses below 500 themselves, so such API errors are not returned here.
+	var apiErr *APIError
+	if err != nil && (resp.StatusCode >= http.StatusInternalServerError || !errors.As(err, &apiErr)) {
+		return nil, resp.StatusCode, err
 	}
 
 	if c.Debug {
-		c.writeLog("MG BOT API Response: %s", res)
+		c.writeLog("MG BOT API Response: %s", resp.Body)
+	}
+
+	return resp.Body, resp.StatusCode, nil
+}
+
+// debugBody returns the request body for the debug log. Only JSON bodies built by the client are logged,
+// other readers (e.g. uploaded files) are not read.
+func debugBody(body io.Reader) interface{} {
+	if buf, ok := body.(*bytes.Buffer); ok {
+		return buf
+	}
+
+	return "[binary data]"
+}
+
+// do sends the request, retrying it according to the client retry policy, and returns the number of attempts.
+// Every attempt is subject to the client-side rate limiter.
+func (c *MgClient) do(req *http.Request) (*http.Response, int, error) {
+	endpoint := endpointPath(req.URL.Path)
+
+	for attempt := 1; ; attempt++ {
+		if err := c.rateLimiter.wait(req.Context(), endpoint); err != nil {
+			return nil, attempt - 1, err
+		}
+
+		resp, err := c.httpClient.Do(req)
+		c.rateLimiter.observe(endpoint, resp)
+
+		wait, retry := c.retryPolicy.retryDelay(req, attempt, resp, err)
+		if !retry {
+			return resp, attempt, err
+		}
+
+		if c.Debug {
+			c.writeLog("MG BOT API Retry: %s %s attempt %d failed, next in %s", req.Method, req.URL, attempt, wait)
+		}
+
+		if err := prepareRetry(req, resp); err != nil {
+			return nil, attempt, err
+		}
+
+		if err := sleepContext(req.Context(), wait); err != nil {
+			return nil, attempt, err
+		}
+	}
+}
+
+// endpointPath returns the request path relative to the API prefix.
+func endpointPath(path string) string {
+	if i := strings.Index(path, prefix); i >= 0 {
+		return path[i+len(prefix):]
 	}
 
-	return res, resp.StatusCode, err
+	return path
 }
 
 func buildRawResponse(resp *http.Response) ([]byte, error) {
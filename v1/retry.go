@@ -0,0 +1,191 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 500 * time.Millisecond
+	defaultRetryMaxBackoff     = 10 * time.Second
+)
+
+// RetryPolicy describes how MgClient retries failed requests.
+//
+// Requests are retried on network errors, 429 Too Many Requests and 500, 502, 503, 504 responses.
+// Only idempotent requests (GET, PUT, DELETE) are retried unless RetryMessageSend is set,
+// which additionally allows retrying POST /messages. Retrying a message may lead to duplicates
+// if the server has processed the first attempt, hence it is opt-in.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts int
+	// InitialBackoff is the base delay before the first retry. It doubles on every next attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff is the upper bound for the delay between attempts. If the server asks to wait longer
+	// via the Retry-After header, the response is returned to the caller as is.
+	MaxBackoff time.Duration
+	// RetryMessageSend enables retries for MessageSend.
+	RetryMessageSend bool
+}
+
+// DefaultRetryPolicy returns RetryPolicy with reasonable defaults.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultRetryMaxAttempts,
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+	}
+}
+
+// OptionRetryPolicy enables retries of failed requests using the provided policy.
+func OptionRetryPolicy(policy RetryPolicy) func(*MgClient) {
+	return func(c *MgClient) {
+		c.retryPolicy = &policy
+	}
+}
+
+// retryDelay reports whether the request should be retried after the given attempt and how long to wait.
+func (p *RetryPolicy) retryDelay(
+	req *http.Request, attempt int, resp *http.Response, err error,
+) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts || !p.retryable(req) {
+		return 0, false
+	}
+
+	if err != nil {
+		if req.Context().Err() != nil {
+			return 0, false
+		}
+
+		return p.backoff(attempt), true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return 0, false
+	}
+
+	wait := p.backoff(attempt)
+	if after, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		if p.MaxBackoff > 0 && after > p.MaxBackoff {
+			return 0, false
+		}
+
+		if after > wait {
+			wait = after
+		}
+	}
+
+	return wait, true
+}
+
+func (p *RetryPolicy) retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		return p.RetryMessageSend && strings.HasSuffix(req.URL.Path, prefix+"/messages")
+	default:
+		return false
+	}
+}
+
+// backoff returns exponential delay with jitter for the given attempt number (starting from 1).
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	return backoffDelay(p.InitialBackoff, p.MaxBackoff, attempt)
+}
+
+// backoffDelay returns exponential delay with jitter for the given attempt number (starting from 1).
+// The delay is randomized within [d/2, d] where d is initial*2^(attempt-1) capped by limit.
+func backoffDelay(initial, limit time.Duration, attempt int) time.Duration {
+	d := initial
+	for i := 1; i < attempt && (limit <= 0 || d < limit); i++ {
+		d *= 2
+	}
+
+	if limit > 0 && d > limit {
+		d = limit
+	}
+
+	if d <= 0 {
+		return 0
+	}
+
+	half := d / 2
+
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter parses Retry-After header value which may be either delay in seconds or HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	if d := date.Sub(now); d > 0 {
+		return d, true
+	}
+
+	return 0, true
+}
+
+// prepareRetry discards the previous response and rewinds the request body.
+func prepareRetry(req *http.Request, resp *http.Response) error {
+	if resp != nil {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+
+	req.Body = body
+
+	return nil
+}
+
+// sleepContext waits for the given duration or until the context is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
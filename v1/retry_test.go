@@ -0,0 +1,143 @@
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func retryTestPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+	}
+}
+
+func TestMgClient_RetryIdempotent(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte(`[{"id": 1, "name": "Test Bot"}]`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken, OptionRetryPolicy(retryTestPolicy()))
+
+	data, status, err := c.Bots(BotsRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, data, 1)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestMgClient_RetryMaxAttempts(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken, OptionRetryPolicy(retryTestPolicy()))
+
+	_, status, err := c.Bots(BotsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadGateway, status)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestMgClient_RetryMessageSend(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"errors": ["too many requests"]}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"message_id": 1, "time": "2018-01-01T00:00:00+03:00"}`))
+	}))
+	defer srv.Close()
+
+	message := MessageSendRequest{Type: MsgTypeText, Scope: MessageScopePublic, Content: "test", ChatID: 1}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		c := New(srv.URL, mgToken, OptionRetryPolicy(retryTestPolicy()))
+
+		_, status, err := c.MessageSend(message)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, status)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+		policy := retryTestPolicy()
+		policy.RetryMessageSend = true
+		c := New(srv.URL, mgToken, OptionRetryPolicy(policy))
+
+		data, status, err := c.MessageSend(message)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, uint64(1), data.MessageID)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestMgClient_RetryAfterTooLong(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"errors": ["too many requests"]}`))
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken, OptionRetryPolicy(retryTestPolicy()))
+
+	_, status, err := c.Bots(BotsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	d, ok := parseRetryAfter("5", now)
+	assert.True(t, ok)
+	assert.Equal(t, 5*time.Second, d)
+
+	d, ok = parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, d)
+
+	_, ok = parseRetryAfter("", now)
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for attempt := 1; attempt < 10; attempt++ {
+		d := p.backoff(attempt)
+		assert.True(t, d > 0)
+		assert.True(t, d <= time.Second)
+	}
+}
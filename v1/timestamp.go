@@ -0,0 +1,129 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TimestampFormat is the format used to send time values to MG. Since and Until request filters are sent
+// in this format in UTC.
+const TimestampFormat = time.RFC3339
+
+// timestampLayouts lists formats of time values returned by MG. Values without time zone are in UTC.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// Timestamp is a time value in MG format. The zero Timestamp stands for an empty or null value.
+//
+// Response types keep timestamps as strings for backward compatibility, use ParseTimestamp and ParseTimestampPtr
+// to read them:
+//
+//	createdAt, err := v1.ParseTimestamp(dialog.CreatedAt)
+//	closedAt, err := v1.ParseTimestampPtr(dialog.ClosedAt)
+//	if !closedAt.IsZero() {
+//		fmt.Println(closedAt.Sub(createdAt.Time))
+//	}
+type Timestamp struct {
+	time.Time
+}
+
+// NewTimestamp returns Timestamp for the time.
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// ParseTimestamp parses the time value returned by MG. An empty string results in the zero Timestamp.
+func ParseTimestamp(value string) (Timestamp, error) {
+	if value == "" {
+		return Timestamp{}, nil
+	}
+
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return Timestamp{Time: t}, nil
+		}
+	}
+
+	return Timestamp{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// ParseTimestampPtr is like ParseTimestamp but accepts nullable values. Nil results in the zero Timestamp.
+func ParseTimestampPtr(value *string) (Timestamp, error) {
+	if value == nil {
+		return Timestamp{}, nil
+	}
+
+	return ParseTimestamp(*value)
+}
+
+// String returns the time in TimestampFormat in UTC, as it is sent in request filters, or an empty string
+// for the zero Timestamp.
+func (t Timestamp) String() string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(TimestampFormat)
+}
+
+// MarshalJSON encodes the zero Timestamp as null and other values as RFC 3339 strings in UTC
+// keeping fractional seconds.
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(t.UTC().Format(time.RFC3339Nano))
+}
+
+// UnmarshalJSON decodes null, empty string and any format supported by ParseTimestamp.
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = Timestamp{}
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	parsed, err := ParseTimestamp(value)
+	if err != nil {
+		return err
+	}
+
+	*t = parsed
+
+	return nil
+}
+
+// prepareTimeFilter checks Since and Until request filters and converts them to UTC, so that they are sent
+// in TimestampFormat like Timestamp.String values (go-querystring encodes time.Time as RFC 3339).
+// Zero values are not checked. Until may be in the future, e.g. for open-ended periods.
+func prepareTimeFilter(since, until *time.Time) error {
+	if since.After(time.Now()) {
+		return fmt.Errorf("%w: since %s is in the future", ErrValidation, NewTimestamp(*since))
+	}
+
+	if !since.IsZero() && !until.IsZero() && !since.Before(*until) {
+		return fmt.Errorf("%w: since %s must be before until %s", ErrValidation, NewTimestamp(*since), NewTimestamp(*until))
+	}
+
+	if !since.IsZero() {
+		*since = since.UTC()
+	}
+
+	if !until.IsZero() {
+		*until = until.UTC()
+	}
+
+	return nil
+}
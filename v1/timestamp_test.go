@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	expected := time.Date(2021, 3, 4, 10, 20, 30, 0, time.UTC)
+
+	for _, value := range []string{
+		"2021-03-04T10:20:30Z",
+		"2021-03-04T13:20:30+03:00",
+		"2021-03-04T10:20:30.000000Z",
+		"2021-03-04T10:20:30",
+		"2021-03-04 10:20:30",
+		"2021-03-04 13:20:30+03:00",
+	} {
+		ts, err := ParseTimestamp(value)
+		require.NoError(t, err, value)
+		assert.True(t, expected.Equal(ts.Time), value)
+	}
+
+	ts, err := ParseTimestamp("2021-03-04")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC), ts.Time)
+
+	ts, err = ParseTimestamp("")
+	require.NoError(t, err)
+	assert.True(t, ts.IsZero())
+
+	_, err = ParseTimestamp("yesterday")
+	assert.Error(t, err)
+}
+
+func TestParseTimestampPtr(t *testing.T) {
+	ts, err := ParseTimestampPtr(nil)
+	require.NoError(t, err)
+	assert.True(t, ts.IsZero())
+
+	value := "2021-03-04T10:20:30Z"
+	ts, err = ParseTimestampPtr(&value)
+	require.NoError(t, err)
+	assert.Equal(t, value, ts.String())
+}
+
+func TestTimestamp_String(t *testing.T) {
+	local := time.Date(2021, 3, 4, 13, 20, 30, 500, time.FixedZone("MSK", 3*60*60))
+
+	assert.Equal(t, "2021-03-04T10:20:30Z", NewTimestamp(local).String())
+	assert.Equal(t, "", Timestamp{}.String())
+}
+
+func TestTimestamp_JSON(t *testing.T) {
+	var data struct {
+		CreatedAt Timestamp  `json:"created_at"`
+		ClosedAt  Timestamp  `json:"closed_at"`
+		RevokedAt Timestamp  `json:"revoked_at"`
+		UpdatedAt *Timestamp `json:"updated_at"`
+	}
+
+	require.NoError(t, json.Unmarshal(
+		[]byte(`{"created_at": "2021-03-04T10:20:30.123456Z", "closed_at": null, "revoked_at": "", "updated_at": null}`),
+		&data,
+	))
+	assert.Equal(t, time.Date(2021, 3, 4, 10, 20, 30, 123456000, time.UTC), data.CreatedAt.Time)
+	assert.True(t, data.ClosedAt.IsZero())
+	assert.True(t, data.RevokedAt.IsZero())
+	assert.Nil(t, data.UpdatedAt)
+
+	encoded, err := json.Marshal(data)
+	require.NoError(t, err)
+	assert.JSONEq(t,
+		`{"created_at": "2021-03-04T10:20:30.123456Z", "closed_at": null, "revoked_at": null, "updated_at": null}`,
+		string(encoded),
+	)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"created_at": 123}`), &data))
+	assert.Error(t, json.Unmarshal([]byte(`{"created_at": "tomorrow"}`), &data))
+}
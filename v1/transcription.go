@@ -0,0 +1,292 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTranscriptionWorkers        = 1
+	defaultTranscriptionQueueSize      = 100
+	defaultTranscriptionMaxAttempts    = 3
+	defaultTranscriptionInitialBackoff = time.Second
+	defaultTranscriptionMaxBackoff     = time.Minute
+)
+
+// Transcriber converts an audio file to text. MG does not transcribe audio itself, the engine is provided by the bot.
+type Transcriber interface {
+	// Transcribe returns the text of the audio. The audio stream is closed by the caller.
+	Transcribe(ctx context.Context, file File, audio io.Reader) (string, error)
+}
+
+// TranscriberFunc is an adapter to use an ordinary function as Transcriber.
+type TranscriberFunc func(ctx context.Context, file File, audio io.Reader) (string, error)
+
+// Transcribe calls f(ctx, file, audio).
+func (f TranscriberFunc) Transcribe(ctx context.Context, file File, audio io.Reader) (string, error) {
+	return f(ctx, file, audio)
+}
+
+// TranscriptionPolicy describes how TranscriptionPipeline processes files.
+type TranscriptionPolicy struct {
+	// Workers is the number of files transcribed concurrently.
+	Workers int
+	// QueueSize is the number of files waiting for transcription. OnMessageNew blocks when the queue is full.
+	QueueSize int
+	// MaxAttempts is the total number of transcription attempts including the first one.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It doubles on every next attempt
+	// and is randomized within [d/2, d] like the delays of RetryPolicy.
+	InitialBackoff time.Duration
+	// MaxBackoff is the upper bound for the delay between attempts.
+	MaxBackoff time.Duration
+}
+
+// DefaultTranscriptionPolicy returns TranscriptionPolicy with reasonable defaults.
+func DefaultTranscriptionPolicy() TranscriptionPolicy {
+	return TranscriptionPolicy{
+		Workers:        defaultTranscriptionWorkers,
+		QueueSize:      defaultTranscriptionQueueSize,
+		MaxAttempts:    defaultTranscriptionMaxAttempts,
+		InitialBackoff: defaultTranscriptionInitialBackoff,
+		MaxBackoff:     defaultTranscriptionMaxBackoff,
+	}
+}
+
+// TranscriptionPipeline transcribes audio attachments of new messages and stores the result in the file metadata
+// (see UpdateFileMetadata). The file is marked as TranscriptionStatusInProgress before transcribing,
+// TranscriptionStatusReady with the text on success and TranscriptionStatusError when all attempts have failed.
+//
+// Example:
+//
+//	pipeline := v1.NewTranscriptionPipeline(client, engine, v1.DefaultTranscriptionPolicy())
+//	pipeline.OnError(func(file v1.File, err error) {
+//		log.Printf("transcription of %s failed: %v", file.ID, err)
+//	})
+//	go pipeline.Run(ctx)
+//
+//	dispatcher := v1.NewWsDispatcher()
+//	dispatcher.OnMessageNew(pipeline.OnMessageNew)
+//
+//	err := client.Listen(ctx, dispatcher.Events(), dispatcher.Handle)
+type TranscriptionPipeline struct {
+	client      *MgClient
+	transcriber Transcriber
+	policy      TranscriptionPolicy
+	queue       chan File
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+	onError func(file File, err error)
+}
+
+// NewTranscriptionPipeline returns the pipeline. Zero policy fields are replaced with the defaults.
+func NewTranscriptionPipeline(
+	client *MgClient, transcriber Transcriber, policy TranscriptionPolicy,
+) *TranscriptionPipeline {
+	defaults := DefaultTranscriptionPolicy()
+	if policy.Workers <= 0 {
+		policy.Workers = defaults.Workers
+	}
+
+	if policy.QueueSize <= 0 {
+		policy.QueueSize = defaults.QueueSize
+	}
+
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = defaults.MaxAttempts
+	}
+
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = defaults.InitialBackoff
+	}
+
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaults.MaxBackoff
+	}
+
+	return &TranscriptionPipeline{
+		client:      client,
+		transcriber: transcriber,
+		policy:      policy,
+		queue:       make(chan File, policy.QueueSize),
+		pending:     map[string]struct{}{},
+	}
+}
+
+// OnError sets the handler which is called when the file transcription has failed.
+// It is safe to call OnError while the pipeline is running.
+func (p *TranscriptionPipeline) OnError(handler func(file File, err error)) {
+	p.mu.Lock()
+	p.onError = handler
+	p.mu.Unlock()
+}
+
+// OnMessageNew queues audio attachments of the message which are not transcribed yet.
+// It can be registered with WsDispatcher.OnMessageNew.
+func (p *TranscriptionPipeline) OnMessageNew(ctx context.Context, data *WsEventMessageNewData) error {
+	if data == nil || data.Message == nil || data.Message.AttachmentList == nil {
+		return nil
+	}
+
+	for _, item := range data.Message.Items {
+		if !IsAudioFile(item.File) || item.Transcription != "" {
+			continue
+		}
+
+		if err := p.Enqueue(ctx, item.File); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Enqueue adds the file to the queue. Files which are already queued or being transcribed are skipped.
+func (p *TranscriptionPipeline) Enqueue(ctx context.Context, file File) error {
+	if !p.acquire(file.ID) {
+		return nil
+	}
+
+	select {
+	case p.queue <- file:
+		return nil
+	case <-ctx.Done():
+		p.release(file.ID)
+		return ctx.Err()
+	}
+}
+
+// Run transcribes queued files until the context is canceled. Files being transcribed at that moment
+// keep TranscriptionStatusInProgress status.
+func (p *TranscriptionPipeline) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for i := 0; i < p.policy.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case file := <-p.queue:
+					err := p.Transcribe(ctx, file)
+					p.release(file.ID)
+
+					if err == nil || ctx.Err() != nil {
+						continue
+					}
+
+					if handler := p.errorHandler(); handler != nil {
+						handler(file, err)
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+// Transcribe transcribes the file synchronously, retrying failed attempts according to the policy,
+// and updates the file metadata. It returns the last transcription error if all attempts have failed.
+func (p *TranscriptionPipeline) Transcribe(ctx context.Context, file File) error {
+	_, _, err := p.client.UpdateFileMetadataContext(ctx, UpdateFileMetadataRequest{
+		ID:                  file.ID,
+		TranscriptionStatus: TranscriptionStatusInProgress,
+	})
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		var text string
+		text, err = p.transcribe(ctx, file)
+		if err == nil {
+			_, _, err = p.client.UpdateFileMetadataContext(ctx, UpdateFileMetadataRequest{
+				ID:                  file.ID,
+				Transcription:       text,
+				TranscriptionStatus: TranscriptionStatusReady,
+			})
+
+			return err
+		}
+
+		if attempt >= p.policy.MaxAttempts || ctx.Err() != nil {
+			break
+		}
+
+		delay := backoffDelay(p.policy.InitialBackoff, p.policy.MaxBackoff, attempt)
+		if p.client.Debug {
+			p.client.writeLog("MG BOT API Transcription: %s attempt %d failed: %v, next in %s",
+				file.ID, attempt, err, delay)
+		}
+
+		if e := sleepContext(ctx, delay); e != nil {
+			return e
+		}
+	}
+
+	if ctx.Err() != nil {
+		return err
+	}
+
+	_, _, e := p.client.UpdateFileMetadataContext(ctx, UpdateFileMetadataRequest{
+		ID:                  file.ID,
+		TranscriptionStatus: TranscriptionStatusError,
+	})
+
+	if e != nil {
+		return fmt.Errorf("%w (status update: %v)", err, e)
+	}
+
+	return err
+}
+
+func (p *TranscriptionPipeline) transcribe(ctx context.Context, file File) (string, error) {
+	audio, _, err := p.client.OpenFile(ctx, file.ID)
+	if err != nil {
+		return "", err
+	}
+	defer audio.Close()
+
+	return p.transcriber.Transcribe(ctx, file, audio)
+}
+
+func (p *TranscriptionPipeline) acquire(fileID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.pending[fileID]; ok {
+		return false
+	}
+
+	p.pending[fileID] = struct{}{}
+
+	return true
+}
+
+func (p *TranscriptionPipeline) release(fileID string) {
+	p.mu.Lock()
+	delete(p.pending, fileID)
+	p.mu.Unlock()
+}
+
+func (p *TranscriptionPipeline) errorHandler() func(file File, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.onError
+}
+
+// IsAudioFile returns true if the attachment is an audio file (e.g. a voice message).
+func IsAudioFile(file File) bool {
+	return file.Type == "audio" || strings.HasPrefix(file.Mime, "audio/")
+}
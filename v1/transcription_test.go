@@ -0,0 +1,173 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func testTranscriptionPolicy() TranscriptionPolicy {
+	return TranscriptionPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+}
+
+// mockTranscription mocks the file metadata updates and the file which is downloaded on every attempt.
+func mockTranscription(fileID string, attempts int) {
+	gock.New(mgURL).
+		Put("/api/bot/v1/files/" + fileID + "/meta").
+		Times(2).
+		Reply(200).
+		JSON(UploadFileResponse{ID: fileID})
+
+	for i := 0; i < attempts; i++ {
+		mockFile(fileID, len(downloadContent))
+	}
+}
+
+// metaStatuses returns the transcription statuses and texts sent in the file metadata updates.
+func metaStatuses(t *testing.T, requests *requestRecorder, fileID string) []string {
+	var statuses []string
+	for _, req := range requests.all() {
+		if req.Method != http.MethodPut || req.Path != "/files/"+fileID+"/meta" {
+			continue
+		}
+
+		var update UpdateFileMetadataRequest
+		require.NoError(t, json.Unmarshal(req.Body, &update))
+		statuses = append(statuses, update.TranscriptionStatus+":"+update.Transcription)
+	}
+
+	return statuses
+}
+
+func TestTranscriptionPipeline_Transcribe(t *testing.T) {
+	defer gock.Off()
+
+	mockTranscription("voice", 3)
+
+	var attempts int
+	transcriber := TranscriberFunc(func(ctx context.Context, file File, audio io.Reader) (string, error) {
+		attempts++
+		if attempts < 3 {
+			return "", errors.New("engine is busy")
+		}
+
+		data, err := ioutil.ReadAll(audio)
+		return strings.ToUpper(string(data)), err
+	})
+
+	var requests requestRecorder
+	pipeline := NewTranscriptionPipeline(client(requests.option()), transcriber, testTranscriptionPolicy())
+	require.NoError(t, pipeline.Transcribe(context.Background(), File{ID: "voice"}))
+
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []string{"in_progress:", "ready:FILE CONTENTS"}, metaStatuses(t, &requests, "voice"))
+	assert.True(t, gock.IsDone())
+}
+
+func TestTranscriptionPipeline_TranscribeError(t *testing.T) {
+	defer gock.Off()
+
+	mockTranscription("voice", 3)
+
+	engineErr := errors.New("unsupported codec")
+	transcriber := TranscriberFunc(func(ctx context.Context, file File, audio io.Reader) (string, error) {
+		return "", engineErr
+	})
+
+	var requests requestRecorder
+	pipeline := NewTranscriptionPipeline(client(requests.option()), transcriber, testTranscriptionPolicy())
+	err := pipeline.Transcribe(context.Background(), File{ID: "voice"})
+
+	assert.True(t, errors.Is(err, engineErr))
+	assert.Equal(t, []string{"in_progress:", "error:"}, metaStatuses(t, &requests, "voice"))
+	assert.True(t, gock.IsDone())
+}
+
+func TestTranscriptionPipeline_OnMessageNew(t *testing.T) {
+	defer gock.Off()
+
+	mockTranscription("voice", 1)
+
+	done := make(chan string, 10)
+	transcriber := TranscriberFunc(func(ctx context.Context, file File, audio io.Reader) (string, error) {
+		done <- file.ID
+		return "text", nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var requests requestRecorder
+	pipeline := NewTranscriptionPipeline(client(requests.option()), transcriber, testTranscriptionPolicy())
+	go func() {
+		_ = pipeline.Run(ctx)
+	}()
+
+	require.NoError(t, pipeline.OnMessageNew(ctx, &WsEventMessageNewData{Message: &Message{
+		AttachmentList: &AttachmentList{Items: []Attachment{
+			{File: File{ID: "voice", Mime: "audio/ogg", Type: "audio"}},
+			{File: File{ID: "photo", Mime: "image/png", Type: "image"}},
+			{File: File{ID: "transcribed", Mime: "audio/ogg", Type: "audio", Transcription: "done"}},
+		}},
+	}}))
+	require.NoError(t, pipeline.OnMessageNew(ctx, &WsEventMessageNewData{Message: &Message{
+		TextMessage: &TextMessage{Content: "hello"},
+	}}))
+
+	select {
+	case id := <-done:
+		assert.Equal(t, "voice", id)
+	case <-time.After(time.Second):
+		t.Fatal("file was not transcribed")
+	}
+
+	assert.Eventually(t, func() bool {
+		return len(metaStatuses(t, &requests, "voice")) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"in_progress:", "ready:text"}, metaStatuses(t, &requests, "voice"))
+	assert.Empty(t, done)
+	assert.Empty(t, metaStatuses(t, &requests, "photo"))
+	assert.Empty(t, metaStatuses(t, &requests, "transcribed"))
+}
+
+func TestTranscriptionPipeline_OnErrorWhileRunning(t *testing.T) {
+	defer gock.Off()
+
+	mockTranscription("voice", 3)
+
+	engineErr := errors.New("unsupported codec")
+	transcriber := TranscriberFunc(func(ctx context.Context, file File, audio io.Reader) (string, error) {
+		return "", engineErr
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pipeline := NewTranscriptionPipeline(client(), transcriber, testTranscriptionPolicy())
+	go func() {
+		_ = pipeline.Run(ctx)
+	}()
+
+	failed := make(chan error, 1)
+	pipeline.OnError(func(file File, err error) {
+		failed <- err
+	})
+	require.NoError(t, pipeline.Enqueue(ctx, File{ID: "voice"}))
+
+	select {
+	case err := <-failed:
+		assert.True(t, errors.Is(err, engineErr))
+	case <-time.After(time.Second):
+		t.Fatal("error handler was not called")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"time"
+
+	"github.com/gorilla/websocket"
 )
 
 const (
@@ -62,6 +64,10 @@ const (
 	MsgTypeFile    string = "file"
 	MsgTypeImage   string = "image"
 
+	TranscriptionStatusInProgress string = "in_progress"
+	TranscriptionStatusReady      string = "ready"
+	TranscriptionStatusError      string = "error"
+
 	MsgOrderStatusCodeNew        = "new"
 	MsgOrderStatusCodeApproval   = "approval"
 	MsgOrderStatusCodeAssembling = "assembling"
@@ -100,100 +106,104 @@ const (
 
 // MgClient type
 type MgClient struct {
-	URL        string `json:"url"`
-	Token      string `json:"token"`
-	Debug      bool   `json:"debug"`
-	httpClient *http.Client
-	logger     BasicLogger `json:"-"`
+	URL         string `json:"url"`
+	Token       string `json:"token"`
+	Debug       bool   `json:"debug"`
+	httpClient  *http.Client
+	logger      BasicLogger  `json:"-"`
+	retryPolicy *RetryPolicy `json:"-"`
+	wsDialer    *websocket.Dialer
+	rateLimiter *rateLimiter
+	middleware  []Middleware
 }
 
 // Request types
 type (
 	BotsRequest struct {
-		ID      uint64 `url:"id,omitempty"`
-		Active  uint8  `url:"active,omitempty"`
-		Self    uint8  `url:"self,omitempty"`
-		Role    string `url:"role,omitempty"`
-		Since   string `url:"since,omitempty"`
-		Until   string `url:"until,omitempty"`
-		SinceID uint64 `url:"since_id,omitempty"`
-		UntilID uint64 `url:"until_id,omitempty"`
-		Limit   int    `url:"limit,omitempty"`
+		ID      uint64    `url:"id,omitempty"`
+		Active  uint8     `url:"active,omitempty"`
+		Self    uint8     `url:"self,omitempty"`
+		Role    string    `url:"role,omitempty"`
+		Since   time.Time `url:"since,omitempty"`
+		Until   time.Time `url:"until,omitempty"`
+		SinceID uint64    `url:"since_id,omitempty"`
+		UntilID uint64    `url:"until_id,omitempty"`
+		Limit   int       `url:"limit,omitempty"`
 	}
 
 	ChannelsRequest struct {
-		ID      uint64   `url:"id,omitempty"`
-		Types   []string `url:"types,omitempty"`
-		Active  uint8    `url:"active,omitempty"`
-		Since   string   `url:"since,omitempty"`
-		Until   string   `url:"until,omitempty"`
-		SinceID uint64   `url:"since_id,omitempty"`
-		UntilID uint64   `url:"until_id,omitempty"`
-		Limit   int      `url:"limit,omitempty"`
+		ID      uint64    `url:"id,omitempty"`
+		Types   []string  `url:"types,omitempty"`
+		Active  uint8     `url:"active,omitempty"`
+		Since   time.Time `url:"since,omitempty"`
+		Until   time.Time `url:"until,omitempty"`
+		SinceID uint64    `url:"since_id,omitempty"`
+		UntilID uint64    `url:"until_id,omitempty"`
+		Limit   int       `url:"limit,omitempty"`
 	}
 
 	UsersRequest struct {
-		ID         uint64 `url:"id,omitempty"`
-		ExternalID string `url:"external_id,omitempty" json:"external_id"`
-		Online     uint8  `url:"online,omitempty"`
-		Active     uint8  `url:"active,omitempty"`
-		Since      string `url:"since,omitempty"`
-		Until      string `url:"until,omitempty"`
-		SinceID    uint64 `url:"since_id,omitempty"`
-		UntilID    uint64 `url:"until_id,omitempty"`
-		Limit      int    `url:"limit,omitempty"`
+		ID         uint64    `url:"id,omitempty"`
+		ExternalID string    `url:"external_id,omitempty" json:"external_id"`
+		Online     uint8     `url:"online,omitempty"`
+		Active     uint8     `url:"active,omitempty"`
+		Since      time.Time `url:"since,omitempty"`
+		Until      time.Time `url:"until,omitempty"`
+		SinceID    uint64    `url:"since_id,omitempty"`
+		UntilID    uint64    `url:"until_id,omitempty"`
+		Limit      int       `url:"limit,omitempty"`
 	}
 
 	CustomersRequest struct {
-		ID          uint64 `url:"id,omitempty"`
-		ChannelID   uint64 `url:"channel_id,omitempty" json:"channel_id"`
-		ChannelType string `url:"channel_type,omitempty" json:"channel_type"`
-		ExternalID  string `url:"external_id,omitempty" json:"external_id"`
-		Since       string `url:"since,omitempty"`
-		Until       string `url:"until,omitempty"`
-		SinceID     uint64 `url:"since_id,omitempty"`
-		UntilID     uint64 `url:"until_id,omitempty"`
-		Limit       int    `url:"limit,omitempty"`
+		ID          uint64    `url:"id,omitempty"`
+		ChannelID   uint64    `url:"channel_id,omitempty" json:"channel_id"`
+		ChannelType string    `url:"channel_type,omitempty" json:"channel_type"`
+		ExternalID  string    `url:"external_id,omitempty" json:"external_id"`
+		Since       time.Time `url:"since,omitempty"`
+		Until       time.Time `url:"until,omitempty"`
+		SinceID     uint64    `url:"since_id,omitempty"`
+		UntilID     uint64    `url:"until_id,omitempty"`
+		Limit       int       `url:"limit,omitempty"`
 	}
 
 	ChatsRequest struct {
-		ID                       uint64 `url:"id,omitempty"`
-		ChannelID                uint64 `url:"channel_id,omitempty" json:"channel_id"`
-		ChannelType              string `url:"channel_type,omitempty" json:"channel_type"`
-		CustomerID               uint64 `url:"customer_id,omitempty" json:"customer_id"`
-		CustomerExternalID       string `url:"customer_external_id,omitempty" json:"customer_external_id"`
-		Since                    string `url:"since,omitempty"`
-		Until                    string `url:"until,omitempty"`
-		Limit                    int    `url:"limit,omitempty"`
-		SinceID                  int    `url:"since_id,omitempty"`
-		UntilID                  int    `url:"until_id,omitempty"`
-		IncludeMassCommunication uint8  `url:"include_mass_communication,omitempty"`
+		ID                       uint64    `url:"id,omitempty"`
+		ChannelID                uint64    `url:"channel_id,omitempty" json:"channel_id"`
+		ChannelType              string    `url:"channel_type,omitempty" json:"channel_type"`
+		CustomerID               uint64    `url:"customer_id,omitempty" json:"customer_id"`
+		CustomerExternalID       string    `url:"customer_external_id,omitempty" json:"customer_external_id"`
+		Since                    time.Time `url:"since,omitempty"`
+		Until                    time.Time `url:"until,omitempty"`
+		Limit                    int       `url:"limit,omitempty"`
+		SinceID                  uint64    `url:"since_id,omitempty"`
+		UntilID                  uint64    `url:"until_id,omitempty"`
+		IncludeMassCommunication uint8     `url:"include_mass_communication,omitempty"`
 	}
 
 	MembersRequest struct {
-		ChatID  uint64 `url:"chat_id,omitempty" json:"chat_id"`
-		UserID  string `url:"user_id,omitempty" json:"user_id"`
-		State   string `url:"state,omitempty"`
-		Since   string `url:"since,omitempty"`
-		Until   string `url:"until,omitempty"`
-		SinceID uint64 `url:"since_id,omitempty"`
-		UntilID uint64 `url:"until_id,omitempty"`
-		Limit   int    `url:"limit,omitempty"`
+		ChatID  uint64    `url:"chat_id,omitempty" json:"chat_id"`
+		UserID  string    `url:"user_id,omitempty" json:"user_id"`
+		State   string    `url:"state,omitempty"`
+		Since   time.Time `url:"since,omitempty"`
+		Until   time.Time `url:"until,omitempty"`
+		SinceID uint64    `url:"since_id,omitempty"`
+		UntilID uint64    `url:"until_id,omitempty"`
+		Limit   int       `url:"limit,omitempty"`
 	}
 
 	DialogsRequest struct {
-		ID                       uint64 `url:"id,omitempty"`
-		ChatID                   string `url:"chat_id,omitempty" json:"chat_id"`
-		UserID                   string `url:"user_id,omitempty" json:"user_id"`
-		BotID                    string `url:"bot_id,omitempty" json:"bot_id"`
-		Assign                   uint8  `url:"assign,omitempty"`
-		Active                   uint8  `url:"active,omitempty"`
-		Since                    string `url:"since,omitempty"`
-		SinceID                  int    `url:"since_id,omitempty"`
-		Until                    string `url:"until,omitempty"`
-		UntilID                  int    `url:"until_id,omitempty"`
-		Limit                    int    `url:"limit,omitempty"`
-		IncludeMassCommunication uint8  `url:"include_mass_communication,omitempty"`
+		ID                       uint64    `url:"id,omitempty"`
+		ChatID                   string    `url:"chat_id,omitempty" json:"chat_id"`
+		UserID                   string    `url:"user_id,omitempty" json:"user_id"`
+		BotID                    string    `url:"bot_id,omitempty" json:"bot_id"`
+		Assign                   uint8     `url:"assign,omitempty"`
+		Active                   uint8     `url:"active,omitempty"`
+		Since                    time.Time `url:"since,omitempty"`
+		SinceID                  uint64    `url:"since_id,omitempty"`
+		Until                    time.Time `url:"until,omitempty"`
+		UntilID                  uint64    `url:"until_id,omitempty"`
+		Limit                    int       `url:"limit,omitempty"`
+		IncludeMassCommunication uint8     `url:"include_mass_communication,omitempty"`
 	}
 
 	DialogAssignRequest struct {
@@ -222,22 +232,22 @@ type (
 	}
 
 	MessagesRequest struct {
-		ID                       []int  `url:"id,omitempty"`
-		ChatID                   uint64 `url:"chat_id,omitempty" json:"chat_id"`
-		DialogID                 uint64 `url:"dialog_id,omitempty" json:"dialog_id"`
-		UserID                   uint64 `url:"user_id,omitempty" json:"user_id"`
-		CustomerID               uint64 `url:"customer_id,omitempty" json:"customer_id"`
-		BotID                    uint64 `url:"bot_id,omitempty" json:"bot_id"`
-		ChannelID                uint64 `url:"channel_id,omitempty" json:"channel_id"`
-		ChannelType              string `url:"channel_type,omitempty" json:"channel_type"`
-		Scope                    string `url:"scope,omitempty"`
-		Type                     string `url:"type,omitempty"`
-		Since                    string `url:"since,omitempty"`
-		Until                    string `url:"until,omitempty"`
-		SinceID                  int    `url:"since_id,omitempty"`
-		UntilID                  int    `url:"until_id,omitempty"`
-		Limit                    int    `url:"limit,omitempty"`
-		IncludeMassCommunication uint8  `url:"include_mass_communication,omitempty"`
+		ID                       []int     `url:"id,omitempty"`
+		ChatID                   uint64    `url:"chat_id,omitempty" json:"chat_id"`
+		DialogID                 uint64    `url:"dialog_id,omitempty" json:"dialog_id"`
+		UserID                   uint64    `url:"user_id,omitempty" json:"user_id"`
+		CustomerID               uint64    `url:"customer_id,omitempty" json:"customer_id"`
+		BotID                    uint64    `url:"bot_id,omitempty" json:"bot_id"`
+		ChannelID                uint64    `url:"channel_id,omitempty" json:"channel_id"`
+		ChannelType              string    `url:"channel_type,omitempty" json:"channel_type"`
+		Scope                    string    `url:"scope,omitempty"`
+		Type                     string    `url:"type,omitempty"`
+		Since                    time.Time `url:"since,omitempty"`
+		Until                    time.Time `url:"until,omitempty"`
+		SinceID                  uint64    `url:"since_id,omitempty"`
+		UntilID                  uint64    `url:"until_id,omitempty"`
+		Limit                    int       `url:"limit,omitempty"`
+		IncludeMassCommunication uint8     `url:"include_mass_communication,omitempty"`
 	}
 
 	MessageSendRequest struct {
@@ -274,13 +284,13 @@ type (
 	}
 
 	CommandsRequest struct {
-		ID      uint64 `url:"id,omitempty"`
-		Name    string `url:"name,omitempty"`
-		Since   string `url:"since,omitempty"`
-		Until   string `url:"until,omitempty"`
-		SinceID uint64 `url:"since_id,omitempty"`
-		UntilID uint64 `url:"until_id,omitempty"`
-		Limit   int    `url:"limit,omitempty"`
+		ID      uint64    `url:"id,omitempty"`
+		Name    string    `url:"name,omitempty"`
+		Since   time.Time `url:"since,omitempty"`
+		Until   time.Time `url:"until,omitempty"`
+		SinceID uint64    `url:"since_id,omitempty"`
+		UntilID uint64    `url:"until_id,omitempty"`
+		Limit   int       `url:"limit,omitempty"`
 	}
 
 	CommandEditRequest struct {
@@ -461,6 +471,9 @@ type (
 		Meta  EventMeta       `json:"meta"`
 		AppID uint            `json:"app_id"`
 		Data  json.RawMessage `json:"data"`
+		// Recovered is true for events restored through REST API after reconnecting (see ReconnectPolicy).
+		// It is never sent by the server and is marshaled only when set.
+		Recovered bool `json:"recovered,omitempty"`
 	}
 
 	EventMeta struct {
@@ -586,12 +599,12 @@ type (
 	}
 
 	MessageOrderCost struct {
-		Value    float32 `json:"value,omitempty"`
+		Value    Decimal `json:"value,omitempty"`
 		Currency string  `json:"currency"`
 	}
 
 	MessageOrderQuantity struct {
-		Value float32 `json:"value"`
+		Value Decimal `json:"value"`
 		Unit  string  `json:"unit"`
 	}
 
@@ -867,4 +880,12 @@ type (
 	WsEventWaitingChatUpdatedData struct {
 		Chat *WaitingChat `json:"chat"`
 	}
+
+	WsEventChatUnreadUpdatedData struct {
+		ChatID uint64 `json:"chat_id"`
+		Count  int    `json:"count"`
+	}
+
+	// WsEventSettingsUpdatedData holds the updated settings by name as is.
+	WsEventSettingsUpdatedData map[string]json.RawMessage
 )
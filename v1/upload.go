@@ -0,0 +1,258 @@
+package v1
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+)
+
+// ErrFileTooLarge is returned when the uploaded file exceeds the limit set by UploadMaxSize.
+// It matches ErrValidation.
+var ErrFileTooLarge = fmt.Errorf("%w: file is too large", ErrValidation)
+
+// UploadOption configures file uploads.
+type UploadOption func(*uploadConfig)
+
+type uploadConfig struct {
+	name        string
+	contentType string
+	size        int64
+	maxSize     int64
+	progress    func(uploaded, total int64)
+	formField   string
+}
+
+// UploadFileName sets the file name sent in Content-Disposition header.
+func UploadFileName(name string) UploadOption {
+	return func(cfg *uploadConfig) {
+		cfg.name = name
+	}
+}
+
+// UploadContentType sets the file MIME type. By default it is detected from the file contents.
+func UploadContentType(contentType string) UploadOption {
+	return func(cfg *uploadConfig) {
+		cfg.contentType = contentType
+	}
+}
+
+// UploadFileSize sets the file size if it can't be determined from the reader.
+// The size is sent as Content-Length, the request is streamed with chunked encoding if the size is unknown.
+func UploadFileSize(size int64) UploadOption {
+	return func(cfg *uploadConfig) {
+		cfg.size = size
+	}
+}
+
+// UploadMaxSize limits the file size. Files of known size are checked before sending,
+// uploads of unknown size are aborted with ErrFileTooLarge when the limit is exceeded.
+func UploadMaxSize(size int64) UploadOption {
+	return func(cfg *uploadConfig) {
+		cfg.maxSize = size
+	}
+}
+
+// UploadMultipart sends the file as multipart/form-data with a single file part named field
+// instead of the raw request body. The part is streamed too, the file name and MIME type are set in its headers.
+func UploadMultipart(field string) UploadOption {
+	return func(cfg *uploadConfig) {
+		cfg.formField = field
+	}
+}
+
+// UploadProgress sets the callback which is called as the file is being sent.
+// Total is -1 if the file size is unknown.
+func UploadProgress(progress func(uploaded, total int64)) UploadOption {
+	return func(cfg *uploadConfig) {
+		cfg.progress = progress
+	}
+}
+
+// UploadFileStream uploads the file streaming it from the reader without buffering in memory.
+// The file is sent as the raw request body, use UploadMultipart to send it as multipart/form-data.
+// The reader size is determined for *os.File and readers with Len method (bytes.Reader, strings.Reader, etc.),
+// use UploadFileSize for other readers. Requests with streamed bodies are not retried.
+//
+// Example:
+//
+//	f, err := os.Open("/tmp/report.pdf")
+//	if err != nil {
+//		return err
+//	}
+//	defer f.Close()
+//
+//	data, status, err := client.UploadFileStream(ctx, f,
+//		v1.UploadFileName("report.pdf"),
+//		v1.UploadMaxSize(20<<20),
+//		v1.UploadProgress(func(uploaded, total int64) {
+//			fmt.Printf("%d/%d\n", uploaded, total)
+//		}),
+//	)
+func (c *MgClient) UploadFileStream(
+	ctx context.Context, reader io.Reader, opts ...UploadOption,
+) (UploadFileResponse, int, error) {
+	var resp UploadFileResponse
+
+	cfg := uploadConfig{size: readerSize(reader)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.maxSize > 0 && cfg.size > cfg.maxSize {
+		return resp, 0, ErrFileTooLarge
+	}
+
+	if cfg.contentType == "" {
+		buffered := bufio.NewReaderSize(reader, sniffLen)
+		cfg.contentType = sniffMime(buffered)
+		reader = buffered
+	}
+
+	body := &uploadReader{reader: reader, total: cfg.size, maxSize: cfg.maxSize, progress: cfg.progress}
+	req, err := c.newUploadRequest(ctx, body, cfg)
+	if err != nil {
+		return resp, 0, err
+	}
+
+	data, status, err := c.sendRequest(req, body)
+	if err != nil {
+		return resp, status, err
+	}
+
+	if status != http.StatusOK {
+		return resp, status, newAPIError(http.MethodPost, "/files/upload", status, data)
+	}
+
+	if e := json.Unmarshal(data, &resp); e != nil {
+		return resp, status, e
+	}
+
+	return resp, status, err
+}
+
+// UploadFilePath uploads the local file. The file name, size and MIME type (by extension) are set automatically.
+func (c *MgClient) UploadFilePath(
+	ctx context.Context, path string, opts ...UploadOption,
+) (UploadFileResponse, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return UploadFileResponse{}, 0, err
+	}
+	defer f.Close()
+
+	defaults := []UploadOption{UploadFileName(filepath.Base(path))}
+	if contentType := mime.TypeByExtension(filepath.Ext(path)); contentType != "" {
+		defaults = append(defaults, UploadContentType(contentType))
+	}
+
+	return c.UploadFileStream(ctx, f, append(defaults, opts...)...)
+}
+
+// newUploadRequest returns the upload request with the file sent either as the raw body or as the multipart form.
+func (c *MgClient) newUploadRequest(ctx context.Context, file io.Reader, cfg uploadConfig) (*http.Request, error) {
+	url := fmt.Sprintf("%s%s/files/upload", c.URL, prefix)
+	if cfg.formField == "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, file)
+		if err != nil {
+			return nil, err
+		}
+
+		req.ContentLength = cfg.size
+		req.Header.Set("Content-Type", cfg.contentType)
+		if cfg.name != "" {
+			req.Header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": cfg.name}))
+		}
+
+		return req, nil
+	}
+
+	// The part headers and the closing boundary are small, so they are built beforehand
+	// and the file is streamed between them.
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+		"name":     cfg.formField,
+		"filename": cfg.name,
+	}))
+	header.Set("Content-Type", cfg.contentType)
+	if _, err := form.CreatePart(header); err != nil {
+		return nil, err
+	}
+
+	headLen := buf.Len()
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+
+	head, tail := buf.Bytes()[:headLen], buf.Bytes()[headLen:]
+	body := io.MultiReader(bytes.NewReader(head), file, bytes.NewReader(tail))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.ContentLength = -1
+	if cfg.size >= 0 {
+		req.ContentLength = int64(len(head)) + cfg.size + int64(len(tail))
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	return req, nil
+}
+
+// readerSize returns the number of bytes left in the reader or -1 if it is unknown.
+func readerSize(reader io.Reader) int64 {
+	switch r := reader.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case *os.File:
+		info, err := r.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+
+		offset, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+
+		return info.Size() - offset
+	default:
+		return -1
+	}
+}
+
+// uploadReader counts uploaded bytes, reports progress and enforces the size limit.
+type uploadReader struct {
+	reader   io.Reader
+	uploaded int64
+	total    int64
+	maxSize  int64
+	progress func(uploaded, total int64)
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.uploaded += int64(n)
+
+	if r.maxSize > 0 && r.uploaded > r.maxSize {
+		return n, ErrFileTooLarge
+	}
+
+	if n > 0 && r.progress != nil {
+		r.progress(r.uploaded, r.total)
+	}
+
+	return n, err
+}
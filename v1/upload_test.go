@@ -0,0 +1,184 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestMgClient_UploadFileStream(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/files/upload").
+		MatchHeader("X-Bot-Token", mgToken).
+		MatchHeader("Content-Type", "image/png").
+		MatchHeader("Content-Disposition", `attachment; filename="photo 1.png"`).
+		AddMatcher(matchUploadBody(pngHeader)).
+		Reply(200).
+		JSON(UploadFileResponse{ID: "photo", MimeType: "image/png"})
+
+	var requests requestRecorder
+	var progress [][2]int64
+	resp, status, err := client(requests.option()).UploadFileStream(context.Background(),
+		bytes.NewReader(pngHeader),
+		UploadFileName("photo 1.png"),
+		UploadProgress(func(uploaded, total int64) {
+			progress = append(progress, [2]int64{uploaded, total})
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "photo", resp.ID)
+	assert.True(t, gock.IsDone())
+
+	sent := requests.all()
+	require.Len(t, sent, 1)
+	assert.Equal(t, int64(len(pngHeader)), sent[0].HTTPRequest.ContentLength)
+	assert.Nil(t, sent[0].Body, "file contents must not be buffered")
+
+	require.NotEmpty(t, progress)
+	assert.Equal(t, [2]int64{int64(len(pngHeader)), int64(len(pngHeader))}, progress[len(progress)-1])
+}
+
+func TestMgClient_UploadFilePath(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/files/upload").
+		MatchHeader("Content-Type", "application/pdf").
+		MatchHeader("Content-Disposition", "attachment; filename=report.pdf").
+		AddMatcher(matchUploadBody([]byte("%PDF-1.4 data"))).
+		Reply(200).
+		JSON(UploadFileResponse{ID: "report", MimeType: "application/pdf"})
+
+	dir, err := ioutil.TempDir("", "upload")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "report.pdf")
+	require.NoError(t, ioutil.WriteFile(path, []byte("%PDF-1.4 data"), 0600))
+
+	var requests requestRecorder
+	resp, _, err := client(requests.option()).UploadFilePath(context.Background(), path)
+	require.NoError(t, err)
+	assert.Equal(t, "report", resp.ID)
+	assert.True(t, gock.IsDone())
+
+	sent := requests.all()
+	require.Len(t, sent, 1)
+	assert.Equal(t, int64(13), sent[0].HTTPRequest.ContentLength)
+}
+
+func TestMgClient_UploadFileStreamMaxSize(t *testing.T) {
+	defer gock.Off()
+
+	var requests requestRecorder
+	c := client(requests.option())
+
+	_, status, err := c.UploadFileStream(context.Background(), strings.NewReader("0123456789"), UploadMaxSize(5))
+	assert.True(t, errors.Is(err, ErrFileTooLarge))
+	assert.True(t, IsValidation(err))
+	assert.Equal(t, 0, status)
+	assert.Empty(t, requests.all(), "file of known size must be checked before sending")
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/files/upload").
+		AddMatcher(matchUploadBody([]byte("0123456789"))).
+		Reply(200).
+		JSON(UploadFileResponse{ID: "file"})
+
+	// The size of the pipe is unknown, so the limit is checked while streaming.
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = pw.Write([]byte("0123456789"))
+		_ = pw.Close()
+	}()
+
+	_, _, err = c.UploadFileStream(context.Background(), pr, UploadMaxSize(5), UploadContentType("text/plain"))
+	assert.True(t, errors.Is(err, ErrFileTooLarge))
+}
+
+func TestMgClient_UploadFileMultipart(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/files/upload").
+		MatchHeader("Content-Type", "^multipart/form-data; boundary=").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			form, err := req.MultipartReader()
+			if err != nil {
+				return false, err
+			}
+
+			part, err := form.NextPart()
+			if err != nil {
+				return false, err
+			}
+
+			data, err := ioutil.ReadAll(part)
+			if err != nil {
+				return false, err
+			}
+
+			return part.FormName() == "file" && part.FileName() == "photo.png" &&
+				part.Header.Get("Content-Type") == "image/png" && bytes.Equal(data, pngHeader), nil
+		}).
+		Reply(200).
+		JSON(UploadFileResponse{ID: "photo", MimeType: "image/png"})
+
+	var requests requestRecorder
+	var progress [][2]int64
+	resp, _, err := client(requests.option()).UploadFileStream(context.Background(),
+		bytes.NewReader(pngHeader),
+		UploadFileName("photo.png"),
+		UploadMultipart("file"),
+		UploadProgress(func(uploaded, total int64) {
+			progress = append(progress, [2]int64{uploaded, total})
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "photo", resp.ID)
+	assert.True(t, gock.IsDone())
+
+	sent := requests.all()
+	require.Len(t, sent, 1)
+	assert.Greater(t, sent[0].HTTPRequest.ContentLength, int64(len(pngHeader)))
+	assert.Empty(t, sent[0].HTTPRequest.Header.Get("Content-Disposition"))
+
+	require.NotEmpty(t, progress)
+	assert.Equal(t, [2]int64{int64(len(pngHeader)), int64(len(pngHeader))}, progress[len(progress)-1])
+}
+
+func TestMgClient_UploadFileDebugLog(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(mgURL).
+		Post("/api/bot/v1/files/upload").
+		MatchHeader("Content-Type", "text/plain; charset=utf-8").
+		AddMatcher(matchUploadBody([]byte("secret file contents"))).
+		Reply(200).
+		JSON(UploadFileResponse{ID: "file", MimeType: "text/plain"})
+
+	var buf bytes.Buffer
+	c := client(OptionDebug(), OptionLogger(log.New(&buf, "", 0)))
+
+	resp, _, err := c.UploadFile(strings.NewReader("secret file contents"))
+	require.NoError(t, err)
+	assert.Equal(t, "file", resp.ID)
+	assert.True(t, gock.IsDone())
+	assert.NotContains(t, buf.String(), "secret file contents")
+	assert.Contains(t, buf.String(), "[binary data]")
+}
@@ -0,0 +1,218 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Message violation codes.
+const (
+	ViolationTypeUnsupported       = "type_unsupported"
+	ViolationTextTooLong           = "text_too_long"
+	ViolationNoteTooLong           = "note_too_long"
+	ViolationTooManyItems          = "too_many_items"
+	ViolationQuotingUnsupported    = "quoting_unsupported"
+	ViolationSuggestionUnsupported = "suggestion_unsupported"
+)
+
+// MessageViolation describes a reason why the channel will reject the message.
+type MessageViolation struct {
+	// Code is one of Violation* constants.
+	Code string
+	// Field is the MessageSendRequest JSON field which violates the channel settings.
+	Field string
+	// Limit is the maximum allowed characters or items count for length violations.
+	Limit int
+	// Actual is the actual characters or items count for length violations.
+	Actual int
+}
+
+// String returns the violation description.
+func (v MessageViolation) String() string {
+	switch v.Code {
+	case ViolationTextTooLong, ViolationNoteTooLong:
+		return fmt.Sprintf("%s: %d characters exceed the limit of %d", v.Field, v.Actual, v.Limit)
+	case ViolationTooManyItems:
+		return fmt.Sprintf("%s: %d items exceed the limit of %d", v.Field, v.Actual, v.Limit)
+	case ViolationTypeUnsupported:
+		return fmt.Sprintf("%s: message type is not supported by the channel", v.Field)
+	case ViolationQuotingUnsupported:
+		return fmt.Sprintf("%s: quoting is not supported by the channel", v.Field)
+	case ViolationSuggestionUnsupported:
+		return fmt.Sprintf("%s: suggestion type is not supported by the channel", v.Field)
+	default:
+		return fmt.Sprintf("%s: %s", v.Field, v.Code)
+	}
+}
+
+// MessageValidationError is returned by ValidateMessage. It matches ErrValidation, so IsValidation returns true.
+type MessageValidationError struct {
+	Violations []MessageViolation
+}
+
+// Error returns all violations joined with "; ".
+func (e *MessageValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.String())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Is reports whether the target is ErrValidation.
+func (e *MessageValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Has returns true if there is a violation with the code.
+func (e *MessageValidationError) Has(code string) bool {
+	for _, v := range e.Violations {
+		if v.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateMessage checks the message against the settings of the target chat channel
+// (see ChannelResponseItem.Settings) and returns *MessageValidationError if the channel will reject it.
+// Settings which are not filled (empty feature or zero limit) are not checked.
+//
+// Example:
+//
+//	err := v1.ValidateMessage(request, channel.Settings)
+//
+//	var validationErr *v1.MessageValidationError
+//	if errors.As(err, &validationErr) {
+//		for _, violation := range validationErr.Violations {
+//			fmt.Printf("%s: %s\n", violation.Code, violation)
+//		}
+//	}
+func ValidateMessage(request MessageSendRequest, settings ChannelSettings) error {
+	var violations []MessageViolation
+
+	switch request.Type {
+	case MsgTypeText, MsgTypeCommand:
+		violations = validateFeature(violations, settings.Text.Creating)
+		violations = validateLength(violations, ViolationTextTooLong, request.Content, int(settings.Text.MaxCharsCount))
+		violations = validateQuoting(violations, request, settings.Text.Quoting)
+	case MsgTypeImage:
+		violations = validateFeature(violations, settings.Image.Creating)
+		violations = validateItems(violations, request, settings.Image.MaxItemsCount)
+		violations = validateLength(violations, ViolationNoteTooLong, request.Content, int(settings.Image.NoteMaxCharsCount))
+		violations = validateQuoting(violations, request, settings.Image.Quoting)
+	case MsgTypeFile:
+		violations = validateFeature(violations, settings.File.Creating)
+		violations = validateItems(violations, request, settings.File.MaxItemsCount)
+		violations = validateLength(violations, ViolationNoteTooLong, request.Content, int(settings.File.NoteMaxCharsCount))
+		violations = validateQuoting(violations, request, settings.File.Quoting)
+	case MsgTypeProduct:
+		violations = validateFeature(violations, settings.Product.Creating)
+	case MsgTypeOrder:
+		violations = validateFeature(violations, settings.Order.Creating)
+	}
+
+	violations = validateSuggestions(violations, request, settings)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &MessageValidationError{Violations: violations}
+}
+
+// ChatChannelSettings returns settings of the chat channel. It returns an error matching ErrNotFound
+// if the chat does not exist and empty settings if the channel is not found.
+func (c *MgClient) ChatChannelSettings(ctx context.Context, chatID uint64) (ChannelSettings, error) {
+	chats, _, err := c.ChatsContext(ctx, ChatsRequest{ID: chatID})
+	if err != nil {
+		return ChannelSettings{}, err
+	}
+
+	if len(chats) == 0 {
+		return ChannelSettings{}, fmt.Errorf("chat %d: %w", chatID, ErrNotFound)
+	}
+
+	channels, _, err := c.ChannelsContext(ctx, ChannelsRequest{ID: chats[0].Channel.ID})
+	if err != nil || len(channels) == 0 {
+		return ChannelSettings{}, err
+	}
+
+	return channels[0].Settings, nil
+}
+
+// channelCanSend returns false if the feature is known and sending is not allowed.
+func channelCanSend(feature string) bool {
+	return feature == "" || feature == ChannelFeatureSend || feature == ChannelFeatureBoth
+}
+
+func validateFeature(violations []MessageViolation, feature string) []MessageViolation {
+	if channelCanSend(feature) {
+		return violations
+	}
+
+	return append(violations, MessageViolation{Code: ViolationTypeUnsupported, Field: "type"})
+}
+
+func validateLength(violations []MessageViolation, code, content string, limit int) []MessageViolation {
+	if length := utf8.RuneCountInString(content); limit > 0 && length > limit {
+		violations = append(violations, MessageViolation{Code: code, Field: "content", Limit: limit, Actual: length})
+	}
+
+	return violations
+}
+
+func validateItems(violations []MessageViolation, request MessageSendRequest, limit int) []MessageViolation {
+	if limit > 0 && len(request.Items) > limit {
+		violations = append(violations, MessageViolation{
+			Code:   ViolationTooManyItems,
+			Field:  "items",
+			Limit:  limit,
+			Actual: len(request.Items),
+		})
+	}
+
+	return violations
+}
+
+func validateQuoting(violations []MessageViolation, request MessageSendRequest, quoting string) []MessageViolation {
+	if request.QuoteMessageId != 0 && !channelCanSend(quoting) {
+		violations = append(violations, MessageViolation{Code: ViolationQuotingUnsupported, Field: "quote_message_id"})
+	}
+
+	return violations
+}
+
+func validateSuggestions(
+	violations []MessageViolation, request MessageSendRequest, settings ChannelSettings,
+) []MessageViolation {
+	if request.TransportAttachments == nil {
+		return violations
+	}
+
+	for i, suggestion := range request.TransportAttachments.Suggestions {
+		var feature string
+		switch suggestion.Type {
+		case SuggestionTypeText:
+			feature = settings.Suggestions.Text
+		case SuggestionTypeEmail:
+			feature = settings.Suggestions.Email
+		case SuggestionTypePhone:
+			feature = settings.Suggestions.Phone
+		default:
+			feature = ChannelFeatureNone
+		}
+
+		if !channelCanSend(feature) {
+			violations = append(violations, MessageViolation{
+				Code:  ViolationSuggestionUnsupported,
+				Field: fmt.Sprintf("transport_attachments.suggestions[%d].type", i),
+			})
+		}
+	}
+
+	return violations
+}
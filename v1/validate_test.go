@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validateTestSettings() ChannelSettings {
+	var settings ChannelSettings
+	settings.Text.Creating = ChannelFeatureBoth
+	settings.Text.Quoting = ChannelFeatureReceive
+	settings.Text.MaxCharsCount = 10
+	settings.Image.Creating = ChannelFeatureBoth
+	settings.Image.MaxItemsCount = 2
+	settings.Image.NoteMaxCharsCount = 5
+	settings.File.Creating = ChannelFeatureReceive
+	settings.Product.Creating = ChannelFeatureNone
+	settings.Order.Creating = ChannelFeatureSend
+	settings.Suggestions.Text = ChannelFeatureBoth
+	settings.Suggestions.Phone = ChannelFeatureNone
+
+	return settings
+}
+
+func TestValidateMessage_Valid(t *testing.T) {
+	settings := validateTestSettings()
+
+	assert.NoError(t, ValidateMessage(MessageSendRequest{Type: MsgTypeText, Content: "Привет мир"}, settings))
+	assert.NoError(t, ValidateMessage(MessageSendRequest{Type: MsgTypeOrder, Order: &MessageOrder{}}, settings))
+	assert.NoError(t, ValidateMessage(MessageSendRequest{Type: MsgTypeText, Content: "long content"}, ChannelSettings{}))
+}
+
+func TestValidateMessage_Text(t *testing.T) {
+	err := ValidateMessage(MessageSendRequest{
+		Type:           MsgTypeText,
+		Content:        strings.Repeat("ы", 11),
+		QuoteMessageId: 1,
+		TransportAttachments: &TransportAttachments{Suggestions: []Suggestion{
+			{Type: SuggestionTypeText, Title: "Yes"},
+			{Type: SuggestionTypePhone},
+		}},
+	}, validateTestSettings())
+
+	var validationErr *MessageValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.True(t, IsValidation(err))
+	assert.Equal(t, []MessageViolation{
+		{Code: ViolationTextTooLong, Field: "content", Limit: 10, Actual: 11},
+		{Code: ViolationQuotingUnsupported, Field: "quote_message_id"},
+		{Code: ViolationSuggestionUnsupported, Field: "transport_attachments.suggestions[1].type"},
+	}, validationErr.Violations)
+	assert.Equal(t, "content: 11 characters exceed the limit of 10; "+
+		"quote_message_id: quoting is not supported by the channel; "+
+		"transport_attachments.suggestions[1].type: suggestion type is not supported by the channel", err.Error())
+}
+
+func TestValidateMessage_Attachments(t *testing.T) {
+	settings := validateTestSettings()
+
+	err := ValidateMessage(MessageSendRequest{
+		Type:    MsgTypeImage,
+		Content: "long note",
+		Items:   []Item{{ID: "1"}, {ID: "2"}, {ID: "3"}},
+	}, settings)
+
+	var validationErr *MessageValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.True(t, validationErr.Has(ViolationTooManyItems))
+	assert.True(t, validationErr.Has(ViolationNoteTooLong))
+
+	err = ValidateMessage(MessageSendRequest{Type: MsgTypeFile, Items: []Item{{ID: "1"}}}, settings)
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []MessageViolation{{Code: ViolationTypeUnsupported, Field: "type"}}, validationErr.Violations)
+}
+
+func TestValidateMessage_Product(t *testing.T) {
+	err := ValidateMessage(MessageSendRequest{Type: MsgTypeProduct, Product: &MessageProduct{}}, validateTestSettings())
+
+	var validationErr *MessageValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.True(t, validationErr.Has(ViolationTypeUnsupported))
+}
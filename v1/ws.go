@@ -0,0 +1,216 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+)
+
+// WsEventHandler handles a single WebSocket event. Returning an error stops Listen and the error is returned to
+// the caller.
+type WsEventHandler func(ctx context.Context, event WsEvent) error
+
+// ListenOption configures Listen. WsOption values (e.g. WsOptionIncludeMassCommunication) can be passed as well.
+type ListenOption interface {
+	applyListen(*listenConfig)
+}
+
+type listenConfig struct {
+	params    []WsParams
+	reconnect *ReconnectPolicy
+}
+
+func (c WsOption) applyListen(cfg *listenConfig) {
+	cfg.params = append(cfg.params, c)
+}
+
+// OptionWsDialer sets custom websocket.Dialer which is used by Listen.
+func OptionWsDialer(dialer *websocket.Dialer) func(*MgClient) {
+	return func(c *MgClient) {
+		c.wsDialer = dialer
+	}
+}
+
+// Listen opens WebSocket connection for the provided events and calls handler for every received event.
+// It blocks until the context is canceled, the connection is closed or the handler returns an error.
+// Cancellation of the context is not considered an error and Listen returns nil in that case.
+// Use ListenReconnect option to restore dropped connections automatically.
+//
+// Example:
+//
+//	var client = v1.New("https://token.url", "cb8ccf05e38a47543ad8477d49bcba99be73bff503ea6")
+//
+//	err := client.Listen(ctx, []string{v1.WsEventMessageNew}, func(ctx context.Context, event v1.WsEvent) error {
+//		fmt.Printf("%s %s\n", event.Type, event.Data)
+//		return nil
+//	})
+//
+//	if err != nil {
+//		fmt.Printf("%v", err)
+//	}
+func (c *MgClient) Listen(ctx context.Context, events []string, handler WsEventHandler, opts ...ListenOption) error {
+	var cfg listenConfig
+	for _, opt := range opts {
+		opt.applyListen(&cfg)
+	}
+
+	if cfg.reconnect != nil {
+		return c.listenReconnect(ctx, events, handler, &cfg)
+	}
+
+	err := c.listenOnce(ctx, events, &cfg, handler, nil)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return err
+}
+
+// listenOnce dials a single connection and reads events from it until it fails.
+// onConnect, if not nil, is called after the connection is established and before reading events.
+func (c *MgClient) listenOnce(
+	ctx context.Context, events []string, cfg *listenConfig, handler WsEventHandler,
+	onConnect func(context.Context) error,
+) error {
+	conn, err := c.dialWs(ctx, events, cfg.params)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		keepAliveWs(ctx, conn, done)
+	}()
+
+	defer func() {
+		close(done)
+		wg.Wait()
+		_ = conn.Close()
+	}()
+
+	if onConnect != nil {
+		if err := onConnect(ctx); err != nil {
+			return err
+		}
+	}
+
+	for {
+		event, err := c.readWsEvent(conn)
+		if err != nil {
+			return err
+		}
+
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *MgClient) dialWs(ctx context.Context, events []string, params []WsParams) (*websocket.Conn, error) {
+	url, headers, err := c.WsMeta(events, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := c.wsDialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+
+	if c.Debug {
+		c.writeLog("MG BOT API WS connect: %s", url)
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, url, headers)
+	if err != nil {
+		if resp != nil {
+			body, _ := buildRawResponse(resp)
+			return nil, newAPIError(http.MethodGet, "/ws", resp.StatusCode, body)
+		}
+
+		return nil, err
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	conn.SetPingHandler(func(data string) error {
+		_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(wsWriteWait))
+
+		var netErr net.Error
+		if errors.Is(err, websocket.ErrCloseSent) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return nil
+		}
+
+		return err
+	})
+
+	return conn, nil
+}
+
+// readWsEvent reads frames until a valid event is received. Malformed frames are skipped.
+func (c *MgClient) readWsEvent(conn *websocket.Conn) (WsEvent, error) {
+	for {
+		var event WsEvent
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return event, err
+		}
+
+		if err := json.Unmarshal(data, &event); err != nil {
+			if c.Debug {
+				c.writeLog("MG BOT API WS malformed event: %s: %v", data, err)
+			}
+
+			continue
+		}
+
+		if c.Debug {
+			c.writeLog("MG BOT API WS event: %s", data)
+		}
+
+		return event, nil
+	}
+}
+
+// keepAliveWs sends pings to the server and closes the connection when the context is done.
+func keepAliveWs(ctx context.Context, conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+			_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteWait))
+			_ = conn.Close()
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				_ = conn.Close()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownWsEvent is returned by DecodeWsEvent for event types without a known data structure.
+var ErrUnknownWsEvent = errors.New("unknown ws event type")
+
+var wsEventData = map[string]func() interface{}{
+	WsEventMessageNew:        func() interface{} { return &WsEventMessageNewData{} },
+	WsEventMessageUpdated:    func() interface{} { return &WsEventMessageUpdatedData{} },
+	WsEventMessageDeleted:    func() interface{} { return &WsEventMessageDeletedData{} },
+	WsEventDialogOpened:      func() interface{} { return &WsEventDialogOpenedData{} },
+	WsEventDialogClosed:      func() interface{} { return &WsEventDialogClosedData{} },
+	WsEventDialogAssign:      func() interface{} { return &WsEventDialogAssignData{} },
+	WsEventChatCreated:       func() interface{} { return &WsEventWaitingChatCreatedData{} },
+	WsEventChatUpdated:       func() interface{} { return &WsEventWaitingChatUpdatedData{} },
+	WsEventChatUnreadUpdated: func() interface{} { return &WsEventChatUnreadUpdatedData{} },
+	WsEventUserOnlineUpdated: func() interface{} { return &WsEventUserOnlineUpdatedData{} },
+	WsEventUserJoined:        func() interface{} { return &EventUserJoinedChatData{} },
+	WsEventUserLeave:         func() interface{} { return &WsEventUserLeaveData{} },
+	WsEventUserUpdated:       func() interface{} { return &WsEventUserUpdatedData{} },
+	WsCustomerUpdated:        func() interface{} { return &WsEventCustomerUpdatedData{} },
+	WsBotUpdated:             func() interface{} { return &WsEventBotUpdatedData{} },
+	WsEventChannelUpdated:    func() interface{} { return &WsEventChannelUpdatedData{} },
+	WsEventSettingsUpdated:   func() interface{} { return &WsEventSettingsUpdatedData{} },
+	WsEventChatsDeleted:      func() interface{} { return &WsEventChatsDeletedData{} },
+}
+
+// DecodeWsEvent decodes event data into the corresponding structure, e.g. *WsEventMessageNewData for message_new.
+// Event types unknown to the client result in ErrUnknownWsEvent.
+//
+// Example:
+//
+//	data, err := v1.DecodeWsEvent(event)
+//	if err != nil {
+//		fmt.Printf("%v", err)
+//	}
+//
+//	if messageNew, ok := data.(*v1.WsEventMessageNewData); ok {
+//		fmt.Printf("%v\n", messageNew.Message.ID)
+//	}
+func DecodeWsEvent(event WsEvent) (interface{}, error) {
+	factory, ok := wsEventData[event.Type]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownWsEvent, event.Type)
+	}
+
+	data := factory()
+	if len(event.Data) == 0 {
+		return data, nil
+	}
+
+	if err := json.Unmarshal(event.Data, data); err != nil {
+		return nil, fmt.Errorf("cannot decode %s event: %w", event.Type, err)
+	}
+
+	return data, nil
+}
+
+type wsEventContextKey struct{}
+
+// WsEventFromContext returns the raw event which is being handled by WsDispatcher.
+// It can be used to access event meta or WsEvent.Recovered flag from typed handlers.
+func WsEventFromContext(ctx context.Context) (WsEvent, bool) {
+	event, ok := ctx.Value(wsEventContextKey{}).(WsEvent)
+	return event, ok
+}
+
+// WsDispatcher decodes WebSocket events and routes them to handlers registered for the event type.
+// Several handlers may be registered for the same type; they are called in the order of registration.
+//
+// Example:
+//
+//	dispatcher := v1.NewWsDispatcher()
+//	dispatcher.OnMessageNew(func(ctx context.Context, data *v1.WsEventMessageNewData) error {
+//		fmt.Printf("%v\n", data.Message.ID)
+//		return nil
+//	})
+//	dispatcher.OnUnknown(func(ctx context.Context, event v1.WsEvent) error {
+//		fmt.Printf("%s\n", event.Type)
+//		return nil
+//	})
+//
+//	err := client.Listen(ctx, dispatcher.Events(), dispatcher.Handle)
+type WsDispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string][]func(ctx context.Context, data interface{}) error
+	fallback WsEventHandler
+}
+
+// NewWsDispatcher returns empty WsDispatcher.
+func NewWsDispatcher() *WsDispatcher {
+	return &WsDispatcher{handlers: map[string][]func(ctx context.Context, data interface{}) error{}}
+}
+
+// Handle decodes the event and calls registered handlers. It can be passed to MgClient.Listen as is.
+func (d *WsDispatcher) Handle(ctx context.Context, event WsEvent) error {
+	d.mu.RLock()
+	handlers := d.handlers[event.Type]
+	fallback := d.fallback
+	d.mu.RUnlock()
+
+	if len(handlers) == 0 {
+		if fallback != nil {
+			return fallback(ctx, event)
+		}
+
+		return nil
+	}
+
+	data, err := DecodeWsEvent(event)
+	if err != nil {
+		return err
+	}
+
+	ctx = context.WithValue(ctx, wsEventContextKey{}, event)
+	for _, handler := range handlers {
+		if err := handler(ctx, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Events returns sorted list of event types with registered handlers. Use it to subscribe in MgClient.Listen.
+func (d *WsDispatcher) Events() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	events := make([]string, 0, len(d.handlers))
+	for event := range d.handlers {
+		events = append(events, event)
+	}
+
+	sort.Strings(events)
+
+	return events
+}
+
+// OnUnknown sets handler for events without registered typed handlers.
+func (d *WsDispatcher) OnUnknown(handler WsEventHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.fallback = handler
+}
+
+func (d *WsDispatcher) on(eventType string, handler func(ctx context.Context, data interface{}) error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[eventType] = append(d.handlers[eventType], handler)
+}
+
+// OnMessageNew registers handler for message_new events.
+func (d *WsDispatcher) OnMessageNew(handler func(ctx context.Context, data *WsEventMessageNewData) error) {
+	d.on(WsEventMessageNew, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventMessageNewData))
+	})
+}
+
+// OnMessageUpdated registers handler for message_updated events.
+func (d *WsDispatcher) OnMessageUpdated(handler func(ctx context.Context, data *WsEventMessageUpdatedData) error) {
+	d.on(WsEventMessageUpdated, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventMessageUpdatedData))
+	})
+}
+
+// OnMessageDeleted registers handler for message_deleted events.
+func (d *WsDispatcher) OnMessageDeleted(handler func(ctx context.Context, data *WsEventMessageDeletedData) error) {
+	d.on(WsEventMessageDeleted, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventMessageDeletedData))
+	})
+}
+
+// OnDialogOpened registers handler for dialog_opened events.
+func (d *WsDispatcher) OnDialogOpened(handler func(ctx context.Context, data *WsEventDialogOpenedData) error) {
+	d.on(WsEventDialogOpened, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventDialogOpenedData))
+	})
+}
+
+// OnDialogClosed registers handler for dialog_closed events.
+func (d *WsDispatcher) OnDialogClosed(handler func(ctx context.Context, data *WsEventDialogClosedData) error) {
+	d.on(WsEventDialogClosed, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventDialogClosedData))
+	})
+}
+
+// OnDialogAssign registers handler for dialog_assign events.
+func (d *WsDispatcher) OnDialogAssign(handler func(ctx context.Context, data *WsEventDialogAssignData) error) {
+	d.on(WsEventDialogAssign, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventDialogAssignData))
+	})
+}
+
+// OnChatCreated registers handler for chat_created events.
+func (d *WsDispatcher) OnChatCreated(handler func(ctx context.Context, data *WsEventWaitingChatCreatedData) error) {
+	d.on(WsEventChatCreated, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventWaitingChatCreatedData))
+	})
+}
+
+// OnChatUpdated registers handler for chat_updated events.
+func (d *WsDispatcher) OnChatUpdated(handler func(ctx context.Context, data *WsEventWaitingChatUpdatedData) error) {
+	d.on(WsEventChatUpdated, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventWaitingChatUpdatedData))
+	})
+}
+
+// OnChatUnreadUpdated registers handler for chat_unread_updated events.
+func (d *WsDispatcher) OnChatUnreadUpdated(
+	handler func(ctx context.Context, data *WsEventChatUnreadUpdatedData) error,
+) {
+	d.on(WsEventChatUnreadUpdated, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventChatUnreadUpdatedData))
+	})
+}
+
+// OnUserOnlineUpdated registers handler for user_online_updated events.
+func (d *WsDispatcher) OnUserOnlineUpdated(
+	handler func(ctx context.Context, data *WsEventUserOnlineUpdatedData) error,
+) {
+	d.on(WsEventUserOnlineUpdated, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventUserOnlineUpdatedData))
+	})
+}
+
+// OnUserJoined registers handler for user_joined_chat events.
+func (d *WsDispatcher) OnUserJoined(handler func(ctx context.Context, data *EventUserJoinedChatData) error) {
+	d.on(WsEventUserJoined, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*EventUserJoinedChatData))
+	})
+}
+
+// OnUserLeave registers handler for user_left_chat events.
+func (d *WsDispatcher) OnUserLeave(handler func(ctx context.Context, data *WsEventUserLeaveData) error) {
+	d.on(WsEventUserLeave, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventUserLeaveData))
+	})
+}
+
+// OnUserUpdated registers handler for user_updated events.
+func (d *WsDispatcher) OnUserUpdated(handler func(ctx context.Context, data *WsEventUserUpdatedData) error) {
+	d.on(WsEventUserUpdated, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventUserUpdatedData))
+	})
+}
+
+// OnCustomerUpdated registers handler for customer_updated events.
+func (d *WsDispatcher) OnCustomerUpdated(handler func(ctx context.Context, data *WsEventCustomerUpdatedData) error) {
+	d.on(WsCustomerUpdated, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventCustomerUpdatedData))
+	})
+}
+
+// OnBotUpdated registers handler for bot_updated events.
+func (d *WsDispatcher) OnBotUpdated(handler func(ctx context.Context, data *WsEventBotUpdatedData) error) {
+	d.on(WsBotUpdated, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventBotUpdatedData))
+	})
+}
+
+// OnChannelUpdated registers handler for channel_updated events.
+func (d *WsDispatcher) OnChannelUpdated(handler func(ctx context.Context, data *WsEventChannelUpdatedData) error) {
+	d.on(WsEventChannelUpdated, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventChannelUpdatedData))
+	})
+}
+
+// OnSettingsUpdated registers handler for settings_updated events.
+func (d *WsDispatcher) OnSettingsUpdated(handler func(ctx context.Context, data *WsEventSettingsUpdatedData) error) {
+	d.on(WsEventSettingsUpdated, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventSettingsUpdatedData))
+	})
+}
+
+// OnChatsDeleted registers handler for chats_deleted events.
+func (d *WsDispatcher) OnChatsDeleted(handler func(ctx context.Context, data *WsEventChatsDeletedData) error) {
+	d.on(WsEventChatsDeleted, func(ctx context.Context, data interface{}) error {
+		return handler(ctx, data.(*WsEventChatsDeletedData))
+	})
+}
@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeWsEvent(t *testing.T) {
+	data, err := DecodeWsEvent(WsEvent{
+		Type: WsEventMessageNew,
+		Data: json.RawMessage(`{"message": {"id": 1, "type": "text", "content": "hello"}}`),
+	})
+	require.NoError(t, err)
+
+	messageNew, ok := data.(*WsEventMessageNewData)
+	require.True(t, ok)
+	assert.Equal(t, uint64(1), messageNew.Message.ID)
+	assert.Equal(t, "hello", messageNew.Message.Content)
+
+	data, err = DecodeWsEvent(WsEvent{
+		Type: WsEventChatsDeleted,
+		Data: json.RawMessage(`{"chat_ids": [1, 2]}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, data.(*WsEventChatsDeletedData).ChatIds)
+
+	_, err = DecodeWsEvent(WsEvent{Type: WsEventDialogClosed, Data: json.RawMessage(`{"dialog": 1}`)})
+	require.Error(t, err)
+
+	data, err = DecodeWsEvent(WsEvent{
+		Type: WsEventChatUnreadUpdated,
+		Data: json.RawMessage(`{"chat_id": 3, "count": 5}`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &WsEventChatUnreadUpdatedData{ChatID: 3, Count: 5}, data)
+
+	_, err = DecodeWsEvent(WsEvent{Type: "some_future_event"})
+	assert.True(t, errors.Is(err, ErrUnknownWsEvent))
+}
+
+// wsEventTypes returns values of all WsEvent* string constants declared in types.go.
+func wsEventTypes(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "types.go", nil, 0)
+	require.NoError(t, err)
+
+	var types []string
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok || spec.Type == nil || len(spec.Values) != len(spec.Names) {
+			return true
+		}
+
+		if ident, ok := spec.Type.(*ast.Ident); !ok || ident.Name != "string" {
+			return true
+		}
+
+		for i, name := range spec.Names {
+			if !strings.HasPrefix(name.Name, "Ws") {
+				continue
+			}
+
+			value, err := strconv.Unquote(spec.Values[i].(*ast.BasicLit).Value)
+			require.NoError(t, err)
+			types = append(types, value)
+		}
+
+		return true
+	})
+
+	return types
+}
+
+func TestDecodeWsEvent_AllTypes(t *testing.T) {
+	types := wsEventTypes(t)
+	require.NotEmpty(t, types)
+
+	for _, eventType := range types {
+		data, err := DecodeWsEvent(WsEvent{Type: eventType, Data: json.RawMessage(`{}`)})
+		require.NoError(t, err, eventType)
+		assert.NotNil(t, data, eventType)
+	}
+}
+
+func TestWsDispatcher_AllTypes(t *testing.T) {
+	d := NewWsDispatcher()
+	dispatcher := reflect.ValueOf(d)
+
+	// Register a no-op handler with every typed On* method.
+	for i := 0; i < dispatcher.NumMethod(); i++ {
+		method := dispatcher.Type().Method(i)
+		if !strings.HasPrefix(method.Name, "On") || method.Name == "OnUnknown" {
+			continue
+		}
+
+		handlerType := method.Type.In(1)
+		dispatcher.Method(i).Call([]reflect.Value{reflect.MakeFunc(handlerType, func([]reflect.Value) []reflect.Value {
+			return []reflect.Value{reflect.Zero(handlerType.Out(0))}
+		})})
+	}
+
+	types := wsEventTypes(t)
+	sort.Strings(types)
+	assert.Equal(t, types, d.Events(), "every event type must have a typed handler")
+
+	for _, eventType := range types {
+		assert.NoError(t, d.Handle(context.Background(), WsEvent{Type: eventType, Data: json.RawMessage(`{}`)}))
+	}
+}
+
+func TestWsDispatcher(t *testing.T) {
+	d := NewWsDispatcher()
+
+	var messages []uint64
+	var recovered []bool
+	d.OnMessageNew(func(ctx context.Context, data *WsEventMessageNewData) error {
+		messages = append(messages, data.Message.ID)
+
+		event, ok := WsEventFromContext(ctx)
+		require.True(t, ok)
+		recovered = append(recovered, event.Recovered)
+
+		return nil
+	})
+
+	var closed []uint64
+	d.OnDialogClosed(func(ctx context.Context, data *WsEventDialogClosedData) error {
+		closed = append(closed, data.Dialog.ID)
+		return nil
+	})
+
+	var unknown []string
+	d.OnUnknown(func(ctx context.Context, event WsEvent) error {
+		unknown = append(unknown, event.Type)
+		return nil
+	})
+
+	assert.Equal(t, []string{WsEventDialogClosed, WsEventMessageNew}, d.Events())
+
+	ctx := context.Background()
+	require.NoError(t, d.Handle(ctx, WsEvent{Type: WsEventMessageNew, Data: json.RawMessage(`{"message": {"id": 1}}`)}))
+	require.NoError(t, d.Handle(ctx, WsEvent{
+		Type:      WsEventMessageNew,
+		Data:      json.RawMessage(`{"message": {"id": 2}}`),
+		Recovered: true,
+	}))
+	require.NoError(t, d.Handle(ctx, WsEvent{Type: WsEventDialogClosed, Data: json.RawMessage(`{"dialog": {"id": 3}}`)}))
+	require.NoError(t, d.Handle(ctx, WsEvent{Type: WsEventUserUpdated, Data: json.RawMessage(`{}`)}))
+	require.NoError(t, d.Handle(ctx, WsEvent{Type: "some_future_event"}))
+
+	assert.Equal(t, []uint64{1, 2}, messages)
+	assert.Equal(t, []bool{false, true}, recovered)
+	assert.Equal(t, []uint64{3}, closed)
+	assert.Equal(t, []string{WsEventUserUpdated, "some_future_event"}, unknown)
+}
+
+func TestWsDispatcher_HandlerError(t *testing.T) {
+	d := NewWsDispatcher()
+	handlerErr := errors.New("handler failed")
+
+	d.OnMessageNew(func(ctx context.Context, data *WsEventMessageNewData) error {
+		return handlerErr
+	})
+
+	err := d.Handle(context.Background(), WsEvent{Type: WsEventMessageNew, Data: json.RawMessage(`{}`)})
+	assert.Equal(t, handlerErr, err)
+}
@@ -0,0 +1,445 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultReconnectInitialBackoff = time.Second
+	defaultReconnectMaxBackoff     = time.Minute
+	wsRecoveryPageLimit            = 100
+	wsRecoveryMaxDelivered         = 1000
+)
+
+// ReconnectPolicy describes how Listen restores a dropped WebSocket connection.
+//
+// After reconnecting Listen back-fills message_new and dialog_opened events which might have been missed while
+// the connection was down. It requests Messages and Dialogs starting from the last seen message and dialog IDs
+// (or from the last event timestamp if no IDs were seen yet) and passes them to the handler as synthetic events
+// with WsEvent.Recovered set to true. Recovered events which then arrive over the new connection are skipped,
+// other live events are passed to the handler as is.
+type ReconnectPolicy struct {
+	// MaxAttempts limits the number of consecutive failed connection attempts. Zero means no limit.
+	MaxAttempts int
+	// InitialBackoff is the base delay before reconnecting. It doubles on every next failed attempt.
+	InitialBackoff time.Duration
+	// MaxBackoff is the upper bound for the delay between attempts.
+	MaxBackoff time.Duration
+	// DisableRecovery turns off back-filling of missed events.
+	DisableRecovery bool
+}
+
+// DefaultReconnectPolicy returns ReconnectPolicy with reasonable defaults.
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		InitialBackoff: defaultReconnectInitialBackoff,
+		MaxBackoff:     defaultReconnectMaxBackoff,
+	}
+}
+
+// ListenReconnect makes Listen reconnect using the provided policy when the connection drops.
+func ListenReconnect(policy ReconnectPolicy) ListenOption {
+	return reconnectOption(policy)
+}
+
+type reconnectOption ReconnectPolicy
+
+func (o reconnectOption) applyListen(cfg *listenConfig) {
+	policy := ReconnectPolicy(o)
+	cfg.reconnect = &policy
+}
+
+// wsHandlerError marks errors returned by the user handler so that they are not treated as connection failures.
+type wsHandlerError struct {
+	err error
+}
+
+func (e *wsHandlerError) Error() string {
+	return e.err.Error()
+}
+
+func (e *wsHandlerError) Unwrap() error {
+	return e.err
+}
+
+// listenReconnect runs listenOnce in a loop until the context is done, the handler fails or the policy gives up.
+func (c *MgClient) listenReconnect(
+	ctx context.Context, events []string, handler WsEventHandler, cfg *listenConfig,
+) error {
+	policy := cfg.reconnect
+	recovery := newWsRecovery(c, events, cfg.params)
+
+	call := func(ctx context.Context, event WsEvent) error {
+		if err := handler(ctx, event); err != nil {
+			return &wsHandlerError{err: err}
+		}
+
+		return nil
+	}
+
+	live := func(ctx context.Context, event WsEvent) error {
+		if !policy.DisableRecovery && recovery.skip(event) {
+			return nil
+		}
+
+		return call(ctx, event)
+	}
+
+	connected := false
+	failures := 0
+
+	for {
+		dialed := false
+		onConnect := func(ctx context.Context) error {
+			dialed = true
+			reconnected := connected
+			connected = true
+
+			if !reconnected || policy.DisableRecovery {
+				return nil
+			}
+
+			if c.Debug {
+				c.writeLog("MG BOT API WS recovering missed events")
+			}
+
+			return recovery.recover(ctx, call)
+		}
+
+		err := c.listenOnce(ctx, events, cfg, live, onConnect)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var handlerErr *wsHandlerError
+		if errors.As(err, &handlerErr) {
+			return handlerErr.err
+		}
+
+		if !wsReconnectable(err) {
+			return err
+		}
+
+		if dialed {
+			failures = 0
+		}
+
+		failures++
+		if policy.MaxAttempts > 0 && failures > policy.MaxAttempts {
+			return err
+		}
+
+		wait := backoffDelay(policy.InitialBackoff, policy.MaxBackoff, failures)
+		if c.Debug {
+			c.writeLog("MG BOT API WS connection lost: %v, reconnecting in %s", err, wait)
+		}
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil
+		}
+	}
+}
+
+// wsReconnectable returns false for handshake errors which will not go away after reconnecting.
+func wsReconnectable(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+
+	return apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// wsRecovery tracks the last seen events and back-fills missed ones through REST API.
+type wsRecovery struct {
+	client          *MgClient
+	messages        bool
+	dialogs         bool
+	massCommunicate bool
+	lastMessageID   uint64
+	lastDialogID    uint64
+	lastEventTime   time.Time
+	// delivered holds events passed to the handler by the last recovery, so that the same events received
+	// over the new connection are skipped. The oldest entries are evicted after wsRecoveryMaxDelivered.
+	delivered      map[wsEventKey]struct{}
+	deliveredOrder []wsEventKey
+}
+
+// wsEventKey identifies message_new and dialog_opened events by the message or dialog ID.
+type wsEventKey struct {
+	eventType string
+	id        uint64
+}
+
+func newWsRecovery(c *MgClient, events []string, params []WsParams) *wsRecovery {
+	r := &wsRecovery{client: c, lastEventTime: time.Now()}
+	for _, event := range events {
+		switch event {
+		case WsEventMessageNew:
+			r.messages = true
+		case WsEventDialogOpened:
+			r.dialogs = true
+		}
+	}
+
+	for _, param := range params {
+		if param == WsOptionIncludeMassCommunication {
+			r.massCommunicate = true
+		}
+	}
+
+	return r
+}
+
+// skip remembers the live event and reports whether it was already delivered by the recovery.
+// Live events are not compared with each other, so events which arrive out of ID order are passed through.
+func (r *wsRecovery) skip(event WsEvent) bool {
+	if event.Meta.Timestamp > 0 {
+		r.lastEventTime = wsEventTime(event.Meta.Timestamp)
+	}
+
+	key, ok := wsRecoveryKey(event)
+	if !ok {
+		return false
+	}
+
+	r.advance(key)
+	if _, ok := r.delivered[key]; ok {
+		delete(r.delivered, key)
+		return true
+	}
+
+	return false
+}
+
+// deliver passes the recovered event to the handler and remembers it.
+func (r *wsRecovery) deliver(ctx context.Context, handler WsEventHandler, event WsEvent, id uint64) error {
+	key := wsEventKey{eventType: event.Type, id: id}
+	r.advance(key)
+
+	if len(r.deliveredOrder) >= wsRecoveryMaxDelivered {
+		delete(r.delivered, r.deliveredOrder[0])
+		r.deliveredOrder = r.deliveredOrder[1:]
+	}
+
+	r.delivered[key] = struct{}{}
+	r.deliveredOrder = append(r.deliveredOrder, key)
+
+	return handler(ctx, event)
+}
+
+// advance updates the last seen message or dialog ID.
+func (r *wsRecovery) advance(key wsEventKey) {
+	switch key.eventType {
+	case WsEventMessageNew:
+		advanceID(&r.lastMessageID, key.id)
+	case WsEventDialogOpened:
+		advanceID(&r.lastDialogID, key.id)
+	}
+}
+
+// wsRecoveryKey returns the key of message_new and dialog_opened events.
+func wsRecoveryKey(event WsEvent) (wsEventKey, bool) {
+	key := wsEventKey{eventType: event.Type}
+
+	switch event.Type {
+	case WsEventMessageNew:
+		var data WsEventMessageNewData
+		if err := json.Unmarshal(event.Data, &data); err != nil || data.Message == nil {
+			return key, false
+		}
+
+		key.id = data.Message.ID
+	case WsEventDialogOpened:
+		var data WsEventDialogOpenedData
+		if err := json.Unmarshal(event.Data, &data); err != nil || data.Dialog == nil {
+			return key, false
+		}
+
+		key.id = data.Dialog.ID
+	default:
+		return key, false
+	}
+
+	return key, true
+}
+
+// recover passes missed messages and dialogs to the handler.
+func (r *wsRecovery) recover(ctx context.Context, handler WsEventHandler) error {
+	r.delivered = map[wsEventKey]struct{}{}
+	r.deliveredOrder = nil
+
+	since := r.lastEventTime
+	if now := time.Now(); since.After(now) {
+		// The event time comes from the server clock which may be ahead of the local one.
+		since = now
+	}
+
+	if r.messages {
+		if err := r.recoverMessages(ctx, since, handler); err != nil {
+			return err
+		}
+	}
+
+	if r.dialogs {
+		if err := r.recoverDialogs(ctx, since, handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *wsRecovery) recoverMessages(ctx context.Context, since time.Time, handler WsEventHandler) error {
+	for {
+		req := MessagesRequest{Limit: wsRecoveryPageLimit}
+		if r.massCommunicate {
+			req.IncludeMassCommunication = 1
+		}
+
+		if r.lastMessageID > 0 {
+			req.SinceID = r.lastMessageID
+		} else {
+			req.Since = since
+		}
+
+		items, _, err := r.client.MessagesContext(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		lastID := r.lastMessageID
+
+		for i := range items {
+			message := items[i].Message
+			event, err := recoveredEvent(WsEventMessageNew, items[i].CreatedAt, WsEventMessageNewData{Message: &message})
+			if err != nil {
+				return err
+			}
+
+			if err := r.deliver(ctx, handler, event, message.ID); err != nil {
+				return err
+			}
+		}
+
+		if len(items) < wsRecoveryPageLimit || r.lastMessageID == lastID {
+			return nil
+		}
+	}
+}
+
+func (r *wsRecovery) recoverDialogs(ctx context.Context, since time.Time, handler WsEventHandler) error {
+	for {
+		req := DialogsRequest{Limit: wsRecoveryPageLimit}
+		if r.massCommunicate {
+			req.IncludeMassCommunication = 1
+		}
+
+		if r.lastDialogID > 0 {
+			req.SinceID = r.lastDialogID
+		} else {
+			req.Since = since
+		}
+
+		items, _, err := r.client.DialogsContext(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		lastID := r.lastDialogID
+
+		for _, item := range items {
+			dialog := dialogFromResponse(item)
+			event, err := recoveredEvent(WsEventDialogOpened, item.CreatedAt, WsEventDialogOpenedData{Dialog: &dialog})
+			if err != nil {
+				return err
+			}
+
+			if err := r.deliver(ctx, handler, event, dialog.ID); err != nil {
+				return err
+			}
+		}
+
+		if len(items) < wsRecoveryPageLimit || r.lastDialogID == lastID {
+			return nil
+		}
+	}
+}
+
+func recoveredEvent(eventType, createdAt string, data interface{}) (WsEvent, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return WsEvent{}, err
+	}
+
+	timestamp := time.Now().Unix()
+	if t, err := ParseTimestamp(createdAt); err == nil && !t.IsZero() {
+		timestamp = t.Unix()
+	}
+
+	return WsEvent{
+		Type:      eventType,
+		Meta:      EventMeta{Timestamp: timestamp},
+		Data:      raw,
+		Recovered: true,
+	}, nil
+}
+
+func dialogFromResponse(item DialogResponseItem) Dialog {
+	dialog := Dialog{
+		ID:        item.ID,
+		Chat:      &Chat{ID: item.ChatID},
+		CreatedAt: item.CreatedAt,
+		Utm:       item.Utm,
+	}
+
+	if item.BeginMessageID > 0 {
+		dialog.BeginMessageID = &item.BeginMessageID
+	}
+
+	if item.EndingMessageID > 0 {
+		dialog.EndingMessageID = &item.EndingMessageID
+	}
+
+	if item.IsAssigned {
+		responsible := item.Responsible
+		dialog.Responsible = &responsible
+	}
+
+	if item.ClosedAt != "" {
+		closedAt := item.ClosedAt
+		dialog.ClosedAt = &closedAt
+	}
+
+	return dialog
+}
+
+// advanceID updates last to id if it is greater and reports whether it was updated.
+func advanceID(last *uint64, id uint64) bool {
+	if id <= *last {
+		return false
+	}
+
+	*last = id
+
+	return true
+}
+
+// Time returns the event time. Both seconds and milliseconds timestamps are supported.
+func (m EventMeta) Time() time.Time {
+	return wsEventTime(m.Timestamp)
+}
+
+// wsEventTime converts EventMeta.Timestamp to time.Time. Both seconds and milliseconds are supported.
+func wsEventTime(timestamp int64) time.Time {
+	const millisThreshold = 1e12
+	if timestamp >= millisThreshold {
+		return time.Unix(0, timestamp*int64(time.Millisecond))
+	}
+
+	return time.Unix(timestamp, 0)
+}
@@ -0,0 +1,172 @@
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func messageNewFrame(id uint64) string {
+	return fmt.Sprintf(`{"type": "message_new", "meta": {"timestamp": 1600000000}, "data": {"message": {"id": %d}}}`, id)
+}
+
+func TestMgClient_ListenReconnect(t *testing.T) {
+	var connections int32
+	upgrader := websocket.Upgrader{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/bot/v1/ws", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if atomic.AddInt32(&connections, 1) == 1 {
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(messageNewFrame(1)))
+			return
+		}
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(messageNewFrame(3)))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(messageNewFrame(4)))
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	mux.HandleFunc("/api/bot/v1/messages", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "1", r.URL.Query().Get("since_id"))
+		_, _ = w.Write([]byte(`[
+			{"id": 2, "created_at": "2020-01-01T00:00:00.000000Z"},
+			{"id": 3, "created_at": "2020-01-01T00:00:01.000000Z"}
+		]`))
+	})
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var ids []uint64
+	var recovered []bool
+	policy := ReconnectPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	err := c.Listen(ctx, []string{WsEventMessageNew}, func(ctx context.Context, event WsEvent) error {
+		var data WsEventMessageNewData
+		require.NoError(t, json.Unmarshal(event.Data, &data))
+
+		ids = append(ids, data.Message.ID)
+		recovered = append(recovered, event.Recovered)
+		if data.Message.ID == 4 {
+			cancel()
+		}
+
+		return nil
+	}, ListenReconnect(policy))
+
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3, 4}, ids)
+	assert.Equal(t, []bool{false, true, true, false}, recovered)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&connections))
+}
+
+func TestMgClient_ListenReconnectOutOfOrder(t *testing.T) {
+	srv := wsTestServer(t, []string{messageNewFrame(101), messageNewFrame(100), messageNewFrame(102)})
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var ids []uint64
+	err := c.Listen(ctx, []string{WsEventMessageNew}, func(ctx context.Context, event WsEvent) error {
+		var data WsEventMessageNewData
+		require.NoError(t, json.Unmarshal(event.Data, &data))
+
+		ids = append(ids, data.Message.ID)
+		if len(ids) == 3 {
+			cancel()
+		}
+
+		return nil
+	}, ListenReconnect(DefaultReconnectPolicy()))
+
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{101, 100, 102}, ids)
+}
+
+func TestWsRecovery_DeliveredLimit(t *testing.T) {
+	r := newWsRecovery(New(mgURL, mgToken), []string{WsEventMessageNew}, nil)
+	r.delivered = map[wsEventKey]struct{}{}
+
+	handler := func(ctx context.Context, event WsEvent) error { return nil }
+	for id := uint64(1); id <= wsRecoveryMaxDelivered+1; id++ {
+		require.NoError(t, r.deliver(context.Background(), handler, WsEvent{Type: WsEventMessageNew}, id))
+	}
+
+	assert.Len(t, r.delivered, wsRecoveryMaxDelivered)
+	assert.Equal(t, uint64(wsRecoveryMaxDelivered+1), r.lastMessageID)
+
+	var frame WsEvent
+	require.NoError(t, json.Unmarshal([]byte(messageNewFrame(1)), &frame))
+	assert.False(t, r.skip(frame))
+
+	require.NoError(t, json.Unmarshal([]byte(messageNewFrame(2)), &frame))
+	assert.True(t, r.skip(frame))
+	assert.False(t, r.skip(frame))
+}
+
+func TestMgClient_ListenReconnectMaxAttempts(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+	policy := ReconnectPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+	err := c.Listen(context.Background(), []string{WsEventMessageNew}, func(ctx context.Context, event WsEvent) error {
+		return nil
+	}, ListenReconnect(policy))
+
+	require.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestMgClient_ListenReconnectHandlerError(t *testing.T) {
+	srv := wsTestServer(t, []string{messageNewFrame(1)})
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+	handlerErr := errors.New("handler failed")
+
+	err := c.Listen(context.Background(), []string{WsEventMessageNew}, func(ctx context.Context, event WsEvent) error {
+		return handlerErr
+	}, ListenReconnect(DefaultReconnectPolicy()))
+
+	assert.Equal(t, handlerErr, err)
+}
+
+func TestWsEventTime(t *testing.T) {
+	assert.Equal(t, int64(1600000000), wsEventTime(1600000000).Unix())
+	assert.Equal(t, int64(1600000000), wsEventTime(1600000000123).Unix())
+	assert.Equal(t, int64(1600000000), EventMeta{Timestamp: 1600000000}.Time().Unix())
+}
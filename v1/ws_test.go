@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func wsTestServer(t *testing.T, frames []string) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Bot-Token") != mgToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"errors": ["invalid token"]}`))
+			return
+		}
+
+		assert.Equal(t, "/api/bot/v1/ws", r.URL.Path)
+		assert.Equal(t, WsEventMessageNew, r.URL.Query().Get("events"))
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for _, frame := range frames {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(frame)); err != nil {
+				return
+			}
+		}
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func TestMgClient_Listen(t *testing.T) {
+	srv := wsTestServer(t, []string{
+		`{"type": "message_new", "meta": {"timestamp": 1}, "data": {"message": {"id": 1}}}`,
+		`malformed`,
+		`{"type": "message_new", "meta": {"timestamp": 2}, "data": {"message": {"id": 2}}}`,
+	})
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var received []WsEvent
+	err := c.Listen(ctx, []string{WsEventMessageNew}, func(ctx context.Context, event WsEvent) error {
+		received = append(received, event)
+		if len(received) == 2 {
+			cancel()
+		}
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, received, 2)
+	assert.Equal(t, WsEventMessageNew, received[0].Type)
+	assert.Equal(t, int64(1), received[0].Meta.Timestamp)
+	assert.Equal(t, int64(2), received[1].Meta.Timestamp)
+}
+
+func TestMgClient_ListenHandlerError(t *testing.T) {
+	srv := wsTestServer(t, []string{`{"type": "message_new", "data": {}}`})
+	defer srv.Close()
+
+	c := New(srv.URL, mgToken)
+	handlerErr := errors.New("handler failed")
+
+	err := c.Listen(context.Background(), []string{WsEventMessageNew}, func(ctx context.Context, event WsEvent) error {
+		return handlerErr
+	})
+
+	assert.True(t, errors.Is(err, handlerErr))
+}
+
+func TestMgClient_ListenUnauthorized(t *testing.T) {
+	srv := wsTestServer(t, nil)
+	defer srv.Close()
+
+	c := New(srv.URL, "wrong_token")
+
+	err := c.Listen(context.Background(), []string{WsEventMessageNew}, func(ctx context.Context, event WsEvent) error {
+		return nil
+	})
+
+	require.Error(t, err)
+	assert.True(t, IsUnauthorized(err))
+	assert.Equal(t, "invalid token", err.Error())
+}
+
+func TestMgClient_WsMetaHTTP(t *testing.T) {
+	c := New("http://127.0.0.1:8080", mgToken)
+
+	url, _, err := c.WsMeta([]string{WsEventMessageNew})
+	require.NoError(t, err)
+	assert.Equal(t, "ws://127.0.0.1:8080/api/bot/v1/ws?events=message_new", url)
+}